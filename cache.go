@@ -0,0 +1,104 @@
+package spindle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Key returns a canonical key of the resolved pagination, the same for
+// every request served the same page however it was spelled:
+//
+//	mode=page&page=1&limit=10&sort=name,-id
+//
+// Page mode keys on the page, offset mode on the start index and cursor
+// mode on the cursor. A snapshot sent by the client is part of the key, as
+// pages served at different snapshots differ; one just issued is not.
+func (p *PageInfo) Key() string {
+	var b strings.Builder
+
+	b.WriteString("mode=")
+	b.WriteString(string(p.Mode))
+	switch p.Mode {
+	case ModeCursor:
+		b.WriteString("&cursor=")
+		b.WriteString(url.QueryEscape(p.Cursor))
+	case ModeOffset:
+		b.WriteString("&offset=")
+		b.WriteString(strconv.Itoa(p.Start()))
+	default:
+		b.WriteString("&page=")
+		b.WriteString(strconv.Itoa(p.Page))
+	}
+	b.WriteString("&limit=")
+	b.WriteString(strconv.Itoa(p.Limit))
+
+	if len(p.Sort) > 0 {
+		fields := make([]string, 0, len(p.Sort))
+		for _, field := range p.Sort {
+			fields = append(fields, sortParam(field))
+		}
+		b.WriteString("&sort=")
+		b.WriteString(url.QueryEscape(strings.Join(fields, ",")))
+	}
+	if p.carriedSnapshot {
+		b.WriteString("&snapshot=")
+		b.WriteString(url.QueryEscape(p.SnapshotToken))
+	}
+
+	return b.String()
+}
+
+// CacheKey is a KeyGenerator for fiber/middleware/cache keying responses
+// on the path, the query parameters other than pagination ones in sorted
+// order, and the Key of the resolved PageInfo, so ?page=1&limit=10 and
+// ?limit=10 share an entry. Register the cache after New:
+//
+//	app.Use(spindle.New())
+//	app.Use(cache.New(cache.Config{KeyGenerator: spindle.CacheKey}))
+//
+// With Config.Transparent the response carries the warnings of the request
+// that filled the cache, so requests whose warnings differ, such as
+// ?limit=500 and ?limit=100, get separate entries keyed on a fingerprint
+// of their warnings. Without a PageInfo the key is the path and the
+// sorted query.
+func CacheKey(c fiber.Ctx) string {
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	pageInfo, ok := FromContext(c)
+	if !ok {
+		return c.Path() + "?" + query.Encode()
+	}
+
+	k := pageInfo.keys.withDefaults()
+	for _, key := range []string{k.page, k.offset, k.limit, k.sort, k.cursor, k.cursorParam} {
+		if key != "" {
+			query.Del(key)
+		}
+	}
+	if pageInfo.carriedSnapshot {
+		query.Del(k.snapshot)
+	}
+	key := c.Path() + "?" + query.Encode() + "|" + pageInfo.Key()
+	if pageInfo.transparent && len(pageInfo.Warnings) > 0 {
+		key += "|warnings=" + warningsFingerprint(pageInfo.Warnings)
+	}
+	return key
+}
+
+// warningsFingerprint hashes every field of warnings, as all of them reach
+// clients in Warning headers or meta.warnings.
+func warningsFingerprint(warnings []Warning) string {
+	h := sha256.New()
+	for _, warning := range warnings {
+		for _, field := range []string{warning.Param, warning.Original, warning.Effective, warning.Message} {
+			h.Write([]byte(field))
+			h.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,139 @@
+package spindle
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/cache"
+)
+
+func TestPageInfoKey(t *testing.T) {
+	t.Parallel()
+
+	sort := []SortField{{Field: "name", Order: ASC}, {Field: "id", Order: DESC}}
+
+	tests := []struct {
+		name     string
+		pageInfo *PageInfo
+		expected string
+	}{
+		{"Page", &PageInfo{Mode: ModePage, Page: 2, Limit: 10, Sort: sort}, "mode=page&page=2&limit=10&sort=name%2C-id"},
+		{"Offset", &PageInfo{Mode: ModeOffset, Page: 1, Offset: 30, Limit: 10}, "mode=offset&offset=30&limit=10"},
+		{"Cursor", &PageInfo{Mode: ModeCursor, Cursor: "abc=", Limit: 5}, "mode=cursor&cursor=abc%3D&limit=5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.pageInfo.Key(); got != tt.expected {
+				t.Errorf("Key() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_PaginateCacheKey(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	app := fiber.New()
+	app.Use(New(Config{SortKey: "sort", AllowedSorts: []string{"id", "name"}}))
+	app.Use(cache.New(cache.Config{KeyGenerator: CacheKey}))
+	app.Get("/", func(c fiber.Ctx) error {
+		calls.Add(1)
+		return c.SendString("ok")
+	})
+
+	testCases := []struct {
+		url   string
+		calls int32
+	}{
+		{"/?page=1&limit=10", 1},
+		{"/?limit=10", 1},
+		{"/?limit=10&page=1&sort=", 1},
+		{"/?sort=secret", 1},
+		{"/?page=2", 2},
+		{"/?q=a&page=1", 3},
+		{"/?page=1&q=a", 3},
+		{"/?q=b", 4},
+		{"/?sort=-name", 5},
+	}
+
+	for _, tc := range testCases {
+		if _, err := app.Test(httptest.NewRequest("GET", tc.url, nil)); err != nil {
+			t.Fatal(err)
+		}
+		if got := calls.Load(); got != tc.calls {
+			t.Errorf("%s: handler calls = %d, want %d", tc.url, got, tc.calls)
+		}
+	}
+}
+
+func Test_PaginateCacheKeyTransparent(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{Transparent: true}))
+	app.Use(cache.New(cache.Config{KeyGenerator: CacheKey}))
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		return c.JSON(NewEnvelope([]int{}, pageInfo))
+	})
+
+	// Each request gets the warnings of its own parameters, not those of
+	// the request that filled the cache.
+	testCases := []struct {
+		url      string
+		original string
+	}{
+		{"/?limit=500", "500"},
+		{"/?limit=100", ""},
+		{"/?limit=200", "200"},
+		{"/?limit=500", "500"},
+	}
+	for _, tc := range testCases {
+		resp, err := app.Test(httptest.NewRequest("GET", tc.url, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var envelope struct {
+			Meta *Meta `json:"meta"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal(err)
+		}
+
+		original := ""
+		if envelope.Meta != nil && len(envelope.Meta.Warnings) > 0 {
+			original = envelope.Meta.Warnings[0].Original
+		}
+		if original != tc.original {
+			t.Errorf("%s: warned about limit %q, want %q", tc.url, original, tc.original)
+		}
+	}
+}
+
+func TestCacheKeyWithoutPageInfo(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Get("/items", func(c fiber.Ctx) error {
+		return c.SendString(CacheKey(c))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/items?b=2&a=1", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body); got != "/items?a=1&b=2" {
+		t.Errorf("CacheKey() = %q, want %q", got, "/items?a=1&b=2")
+	}
+}
@@ -0,0 +1,89 @@
+package spindle
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// canonicalQuery returns the query string that serves p with the fewest
+// parameters: other parameters are kept, pagination parameters at their
+// default value are dropped, sort fields that are not allowed are removed
+// and keys are sorted. sorts is the resolved sort without the tiebreaker.
+func canonicalQuery(query url.Values, cfg Config, p *PageInfo, sorts []SortField, defaultLimit int) string {
+	values := make(url.Values, len(query))
+	for key, v := range query {
+		switch key {
+		case cfg.PageKey, cfg.OffsetKey, cfg.LimitKey, cfg.SortKey, cfg.CursorKey, cfg.CursorParam:
+		default:
+			values[key] = v
+		}
+	}
+
+	switch {
+	case p.Mode == ModeCursor:
+		if p.Cursor != "" {
+			values.Set(cfg.CursorKey, p.Cursor)
+		}
+	case p.Offset > 0:
+		values.Set(cfg.OffsetKey, strconv.Itoa(p.Offset))
+	case p.Page != cfg.DefaultPage:
+		values.Set(cfg.PageKey, strconv.Itoa(p.Page))
+	}
+	if p.Limit != defaultLimit {
+		values.Set(cfg.LimitKey, strconv.Itoa(p.Limit))
+	}
+	if cfg.SortKey != "" && !slices.Equal(sorts, parseSortQuery("", cfg.AllowedSorts, cfg.DefaultSort)) {
+		fields := make([]string, 0, len(sorts))
+		for _, field := range sorts {
+			fields = append(fields, sortParam(field))
+		}
+		values.Set(cfg.SortKey, strings.Join(fields, ","))
+	}
+
+	// Commas are left unescaped so sort lists stay readable.
+	return strings.ReplaceAll(values.Encode(), "%2C", ",")
+}
+
+// CanonicalURL returns baseURL with the canonical query string of the
+// request, as used by Config.Canonical for redirects and rel=canonical
+// links. Without Config.Canonical it returns baseURL.
+func (p *PageInfo) CanonicalURL(baseURL string) string {
+	if p.canonical == "" {
+		return baseURL
+	}
+	return baseURL + "?" + p.canonical
+}
+
+// redirectsToCanonical reports whether a request must be redirected to
+// its canonical URL: only GET and HEAD requests whose query differs. A
+// request clamped to the limit or depth caps is served as is, as the caps
+// may change or depend on the client, and a 301 is cached for good.
+func redirectsToCanonical(method, rawQuery string, p *PageInfo) bool {
+	if !p.hasCanonical || p.clamped || (method != http.MethodGet && method != http.MethodHead) {
+		return false
+	}
+	return rawQuery != p.canonical
+}
+
+// canonicalLink renders a Link header value pointing at the canonical URL.
+func canonicalLink(baseURL string, p *PageInfo) string {
+	return "<" + p.CanonicalURL(baseURL) + `>; rel="canonical"`
+}
+
+// writeCanonical redirects a Fiber request to its canonical URL, or sets
+// the rel=canonical Link header. It reports whether it redirected.
+func writeCanonical(c fiber.Ctx, p *PageInfo) (bool, error) {
+	if !p.hasCanonical {
+		return false, nil
+	}
+	if redirectsToCanonical(c.Method(), string(c.Request().URI().QueryString()), p) {
+		return true, c.Redirect().Status(fiber.StatusMovedPermanently).To(p.CanonicalURL(c.Path()))
+	}
+	c.Append(fiber.HeaderLink, canonicalLink(c.BaseURL()+c.Path(), p))
+	return false, nil
+}
@@ -0,0 +1,151 @@
+package spindle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func Test_PaginateCanonical(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{
+		SortKey:      "sort",
+		DefaultSort:  "name",
+		AllowedSorts: []string{"name", "price"},
+		Canonical:    true,
+	}))
+	app.Get("/products", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post("/products", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	testCases := []struct {
+		name     string
+		method   string
+		url      string
+		status   int
+		location string
+	}{
+		{"Canonical", "GET", "/products?category=tea&page=2", 200, ""},
+		{"Default page", "GET", "/products?page=1", 301, "/products"},
+		{"Default limit", "GET", "/products?limit=10&page=3", 301, "/products?page=3"},
+		{"Default sort", "GET", "/products?sort=name", 301, "/products"},
+		{"Unknown sort", "GET", "/products?sort=-price,secret", 301, "/products?sort=-price"},
+		{"Ordered", "GET", "/products?page=2&category=tea", 301, "/products?category=tea&page=2"},
+		{"Clamped limit", "GET", "/products?limit=500", 200, ""},
+		{"Cursor", "GET", "/products?cursor=eyJpZCI6MX0", 200, ""},
+		{"Post is not redirected", "POST", "/products?page=1", 200, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tc.method, tc.url, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tc.status)
+			}
+			if location := resp.Header.Get("Location"); location != tc.location {
+				t.Errorf("Location = %q, want %q", location, tc.location)
+			}
+		})
+	}
+}
+
+func Test_PaginateCanonicalDepth(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []DepthPolicy{DepthClamp, DepthCursor} {
+		app := fiber.New()
+		app.Use(New(Config{MaxPage: 5, MaxOffset: 100, DepthPolicy: policy, Canonical: true}))
+		app.Get("/products", func(c fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		// Requests past the depth limits are served without a permanent
+		// redirect, even when their query is not canonical otherwise.
+		for _, url := range []string{"/products?page=9", "/products?offset=500", "/products?page=9&limit=10"} {
+			resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != fiber.StatusOK || resp.Header.Get("Link") == "" {
+				t.Errorf("policy %v, %s: status, Link = %d, %q, want 200 and a canonical link",
+					policy, url, resp.StatusCode, resp.Header.Get("Link"))
+			}
+		}
+	}
+}
+
+func Test_PaginateCanonicalLink(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{Canonical: true}))
+	app.Get("/products", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "http://shop.example/products?page=2", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link := resp.Header.Get("Link"); link != `<http://shop.example/products?page=2>; rel="canonical"` {
+		t.Errorf("Link = %q", link)
+	}
+}
+
+func Test_PaginateCanonicalDisabled(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{Canonical: true, RangeHeader: true}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/?page=1", nil)
+	req.Header.Set("Range", "items=0-9")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode == fiber.StatusMovedPermanently || resp.Header.Get("Link") != "" {
+		t.Errorf("status = %d, Link = %q, want no canonicalization for Range requests", resp.StatusCode, resp.Header.Get("Link"))
+	}
+
+	plain := NewParser()
+	pageInfo, err := plain.Parse(Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pageInfo.CanonicalURL("/x"); got != "/x" {
+		t.Errorf("CanonicalURL() = %q, want /x", got)
+	}
+}
+
+func TestNewHTTPCanonical(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHTTP(Config{Canonical: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/items?limit=10&page=1", nil))
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/items" {
+		t.Errorf("status, Location = %d, %q, want 301, /items", rec.Code, rec.Header().Get("Location"))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "http://example.com/items?page=4", nil))
+	if link := rec.Header().Get("Link"); link != `<http://example.com/items?page=4>; rel="canonical"` {
+		t.Errorf("Link = %q", link)
+	}
+}
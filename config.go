@@ -1,6 +1,10 @@
 package spindle
 
-import "github.com/gofiber/fiber/v3"
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v3"
+)
 
 // Config defines the config for the pagination middleware.
 type Config struct {
@@ -13,12 +17,25 @@ type Config struct {
 	// DefaultPage is the default page number.
 	DefaultPage int
 
+	// OffsetKey is the query string key for offset.
+	OffsetKey string
+
 	// LimitKey is the query string key for limit.
 	LimitKey string
 
 	// DefaultLimit is the default items per page.
 	DefaultLimit int
 
+	// MaxLimitFunc returns the largest limit a request may use, so the cap
+	// can follow auth claims, API key tiers or headers. Results below 1
+	// fall back to MaxLimit. Fiber-specific; see Input.MaxLimit.
+	MaxLimitFunc func(c fiber.Ctx) int
+
+	// DefaultLimitFunc returns the limit used when a request does not set
+	// one. Results below 1 fall back to DefaultLimit. Fiber-specific; see
+	// Input.DefaultLimit.
+	DefaultLimitFunc func(c fiber.Ctx) int
+
 	// SortKey is the query string key for sort.
 	SortKey string
 
@@ -28,11 +45,115 @@ type Config struct {
 	// AllowedSorts is the list of allowed sort fields.
 	AllowedSorts []string
 
+	// Tiebreaker names a column with a unique value per row, such as "id".
+	// It is appended to the sort when absent, following the direction of
+	// the last sort field, so rows with equal sort values are neither
+	// skipped nor repeated between pages. It need not be in AllowedSorts.
+	Tiebreaker string
+
+	// MaxSortFields is the most sort fields a request may use, not counting
+	// the tiebreaker. Zero means no limit. Extra fields are dropped, or
+	// rejected with 400 in strict mode.
+	MaxSortFields int
+
+	// SortConflict decides how a field sorted in both directions is served.
+	// Defaults to SortFirstWins. Repeated fields are always collapsed, and
+	// in strict mode both repetitions and conflicts are rejected with 400.
+	SortConflict SortConflictPolicy
+
 	// CursorKey is the query string key for cursor-based pagination.
 	CursorKey string
 
 	// CursorParam is an optional alias for the cursor query key.
 	CursorParam string
+
+	// Snapshot serves page and offset requests at a consistent point in
+	// time. The first request is issued a snapshot, exposed as
+	// PageInfo.Snapshot, which later pages carry under SnapshotKey so rows
+	// inserted meanwhile do not shift them. A page past the first without
+	// a snapshot is issued a new one, or rejected with 400 in strict mode.
+	Snapshot bool
+
+	// SnapshotKey is the query string key for the snapshot token.
+	SnapshotKey string
+
+	// SnapshotSecret signs snapshot tokens with HMAC-SHA256 so clients
+	// cannot forge them. Without it only RFC 3339 timestamps are accepted
+	// in tokens, so PageInfo.SetSnapshot needs it for other values.
+	SnapshotSecret []byte
+
+	// MaxPage is the deepest page a request may ask for. Zero means no limit.
+	MaxPage int
+
+	// MaxOffset is the largest start index a page or offset request may
+	// reach. Zero means no limit.
+	MaxOffset int
+
+	// DepthPolicy decides how requests beyond MaxPage or MaxOffset are
+	// served. Defaults to DepthClamp.
+	DepthPolicy DepthPolicy
+
+	// Mode selects page, offset or cursor pagination. ModeAuto picks the
+	// mode from the parameters present.
+	Mode Mode
+
+	// Strict rejects requests that mix parameters of different modes,
+	// such as page and offset, with 400 instead of resolving them by
+	// precedence. Malformed or negative limits are rejected too.
+	Strict bool
+
+	// BindCursor binds issued cursors to the other query parameters of the
+	// request that produced them, and to its body and form fields when
+	// those are sources. A cursor sent with different parameters is
+	// rejected with 400. The limit may still change between requests.
+	BindCursor bool
+
+	// RangeHeader reads offset and limit from a Range request header such as
+	// "Range: items=0-24". When the handler sets a total on the PageInfo,
+	// the response gets a matching Content-Range header and 206 status.
+	RangeHeader bool
+
+	// RangeUnit is the unit expected in the Range header.
+	RangeUnit string
+
+	// Sources lists where pagination parameters are read from, in order of
+	// precedence. The first source holding a key wins.
+	Sources []Source
+
+	// BodyLimit is the largest JSON body, in bytes, NewHTTP reads
+	// parameters from when SourceBody is listed. Larger bodies are passed
+	// to the handler unread. Defaults to 4 MB, the default body limit of
+	// Fiber, which New relies on instead.
+	BodyLimit int
+
+	// Logger, when set, logs every parameter the parser changes instead of
+	// rejecting, with its original and effective values. The changes are
+	// also listed in PageInfo.Warnings.
+	Logger *slog.Logger
+
+	// Transparent reports coerced parameters to clients, as a Warning
+	// header per distinct change, at most ten, and in the meta.warnings
+	// field of envelopes built with NewEnvelope.
+	Transparent bool
+
+	// Canonical redirects GET and HEAD requests whose query string is not
+	// canonical with 301: pagination parameters at their default value are
+	// dropped, sort fields that are not allowed are removed and keys are
+	// sorted. Requests clamped to the limit or depth caps are not
+	// redirected. Other responses get a rel=canonical Link header. It applies
+	// when parameters are read from the query string and not from a Range
+	// header.
+	Canonical bool
+
+	// Observer is told about every resolved PageInfo and every rejected
+	// request. See the promx and otelx packages for metrics.
+	Observer Observer
+
+	// Routes holds per-route overrides, keyed by route name or registered
+	// path such as "/users/:id". Fields set in an override replace those of
+	// this config for requests served by the matching route. Routes is
+	// Fiber-specific and ignored by NewHTTP.
+	Routes map[string]RouteConfig
 }
 
 // ConfigDefault is the default config.
@@ -40,9 +161,28 @@ var ConfigDefault = Config{
 	Next:         nil,
 	PageKey:      "page",
 	DefaultPage:  1,
+	OffsetKey:    "offset",
 	LimitKey:     "limit",
 	DefaultLimit: 10,
 	CursorKey:    "cursor",
+	SnapshotKey:  "snapshot",
+	Mode:         ModeAuto,
+	RangeUnit:    "items",
+	Sources:      []Source{SourceQuery},
+	BodyLimit:    4 << 20,
+}
+
+// ConfigAIP158 is a preset following Google AIP-158: page_size and
+// page_token query keys, a page_size of 0 meaning the server default,
+// and page tokens bound to the request that issued them. It paginates by
+// cursor only and is strict, so negative page sizes and page or offset
+// parameters are rejected with 400.
+var ConfigAIP158 = Config{
+	LimitKey:   "page_size",
+	CursorKey:  "page_token",
+	BindCursor: true,
+	Mode:       ModeCursor,
+	Strict:     true,
 }
 
 func configDefault(config ...Config) Config {
@@ -58,6 +198,9 @@ func configDefault(config ...Config) Config {
 	if cfg.PageKey == "" {
 		cfg.PageKey = ConfigDefault.PageKey
 	}
+	if cfg.OffsetKey == "" {
+		cfg.OffsetKey = ConfigDefault.OffsetKey
+	}
 	if cfg.DefaultLimit < 1 {
 		cfg.DefaultLimit = ConfigDefault.DefaultLimit
 	}
@@ -70,6 +213,21 @@ func configDefault(config ...Config) Config {
 	if cfg.CursorKey == "" {
 		cfg.CursorKey = ConfigDefault.CursorKey
 	}
+	if cfg.SnapshotKey == "" {
+		cfg.SnapshotKey = ConfigDefault.SnapshotKey
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = ConfigDefault.Mode
+	}
+	if cfg.RangeUnit == "" {
+		cfg.RangeUnit = ConfigDefault.RangeUnit
+	}
+	if len(cfg.Sources) == 0 {
+		cfg.Sources = ConfigDefault.Sources
+	}
+	if cfg.BodyLimit < 1 {
+		cfg.BodyLimit = ConfigDefault.BodyLimit
+	}
 
 	return cfg
 }
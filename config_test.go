@@ -81,3 +81,50 @@ func TestConfigNegativeDefaults(t *testing.T) {
 		t.Errorf("DefaultLimit = %d, want %d", cfg.DefaultLimit, 10)
 	}
 }
+
+func TestConfigAIP158(t *testing.T) {
+	t.Parallel()
+
+	cfg := configDefault(ConfigAIP158)
+	if cfg.LimitKey != "page_size" {
+		t.Errorf("LimitKey = %q, want %q", cfg.LimitKey, "page_size")
+	}
+	if cfg.CursorKey != "page_token" {
+		t.Errorf("CursorKey = %q, want %q", cfg.CursorKey, "page_token")
+	}
+	if !cfg.BindCursor {
+		t.Error("BindCursor = false, want true")
+	}
+	if cfg.Mode != ModeCursor || !cfg.Strict {
+		t.Errorf("Mode, Strict = %q, %v, want %q, true", cfg.Mode, cfg.Strict, ModeCursor)
+	}
+	if cfg.DefaultLimit != 10 {
+		t.Errorf("DefaultLimit = %d, want %d", cfg.DefaultLimit, 10)
+	}
+}
+
+func TestConfigDefaultSources(t *testing.T) {
+	t.Parallel()
+
+	cfg := configDefault(Config{})
+	if len(cfg.Sources) != 1 || cfg.Sources[0] != SourceQuery {
+		t.Errorf("Sources = %v, want [SourceQuery]", cfg.Sources)
+	}
+
+	cfg = configDefault(Config{Sources: []Source{SourceBody, SourceQuery}})
+	if len(cfg.Sources) != 2 || cfg.Sources[0] != SourceBody {
+		t.Errorf("Sources = %v, want [SourceBody SourceQuery]", cfg.Sources)
+	}
+}
+
+func TestConfigDefaultMode(t *testing.T) {
+	t.Parallel()
+
+	cfg := configDefault(Config{})
+	if cfg.Mode != ModeAuto {
+		t.Errorf("Mode = %q, want %q", cfg.Mode, ModeAuto)
+	}
+	if cfg.OffsetKey != "offset" {
+		t.Errorf("OffsetKey = %q, want %q", cfg.OffsetKey, "offset")
+	}
+}
@@ -0,0 +1,55 @@
+package spindle
+
+import "errors"
+
+// DepthPolicy decides how requests beyond MaxPage or MaxOffset are served.
+type DepthPolicy int
+
+const (
+	// DepthClamp serves the deepest allowed page instead.
+	DepthClamp DepthPolicy = iota
+	// DepthReject rejects the request with 400, hinting at cursor pagination.
+	DepthReject
+	// DepthCursor serves the request in cursor mode from the first page,
+	// so keyset-capable handlers avoid the deep scan altogether.
+	DepthCursor
+)
+
+// ErrDepthExceeded is returned by DepthReject when a request goes past
+// MaxPage or MaxOffset.
+var ErrDepthExceeded = errors.New("pagination depth exceeded, use cursor pagination")
+
+// limitDepth applies MaxPage and MaxOffset to a page or offset request.
+// It returns the page, offset and mode the request is served with. MaxPage
+// only applies in page mode.
+func limitDepth(cfg Config, mode Mode, page, offset, limit int) (int, int, Mode, error) {
+	start := offset
+	if mode != ModeOffset {
+		start = (page - 1) * limit
+	}
+
+	pageExceeded := mode != ModeOffset && cfg.MaxPage > 0 && page > cfg.MaxPage
+	offsetExceeded := cfg.MaxOffset > 0 && start > cfg.MaxOffset
+	if !pageExceeded && !offsetExceeded {
+		return page, offset, mode, nil
+	}
+
+	switch cfg.DepthPolicy {
+	case DepthReject:
+		return 0, 0, "", ErrDepthExceeded
+	case DepthCursor:
+		return 0, 0, ModeCursor, nil
+	}
+
+	if pageExceeded {
+		page = cfg.MaxPage
+	}
+	if offsetExceeded {
+		if mode == ModeOffset {
+			offset = cfg.MaxOffset
+		} else {
+			page = min(page, cfg.MaxOffset/limit+1)
+		}
+	}
+	return page, offset, mode, nil
+}
@@ -0,0 +1,117 @@
+package spindle
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestLimitDepth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		mode       Mode
+		page       int
+		offset     int
+		wantPage   int
+		wantOffset int
+		wantMode   Mode
+		wantErr    error
+	}{
+		{"No limits", Config{}, ModePage, 50000, 0, 50000, 0, ModePage, nil},
+		{"Within MaxPage", Config{MaxPage: 10}, ModePage, 10, 0, 10, 0, ModePage, nil},
+		{"Clamp page", Config{MaxPage: 10}, ModePage, 11, 0, 10, 0, ModePage, nil},
+		{"Clamp offset", Config{MaxOffset: 500}, ModeOffset, 1, 900, 1, 500, ModeOffset, nil},
+		{"MaxPage ignored by offset", Config{MaxPage: 2}, ModeOffset, 0, 50, 0, 50, ModeOffset, nil},
+		{"Clamp page by offset", Config{MaxOffset: 500}, ModePage, 100, 0, 51, 0, ModePage, nil},
+		{"Reject page", Config{MaxPage: 10, DepthPolicy: DepthReject}, ModePage, 11, 0, 0, 0, "", ErrDepthExceeded},
+		{"Reject offset", Config{MaxOffset: 500, DepthPolicy: DepthReject}, ModeOffset, 1, 501, 0, 0, "", ErrDepthExceeded},
+		{"Switch to cursor", Config{MaxPage: 10, DepthPolicy: DepthCursor}, ModePage, 11, 0, 0, 0, ModeCursor, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, offset, mode, err := limitDepth(tt.cfg, tt.mode, tt.page, tt.offset, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("limitDepth() error = %v, want %v", err, tt.wantErr)
+			}
+			if page != tt.wantPage || offset != tt.wantOffset || mode != tt.wantMode {
+				t.Errorf("limitDepth() = (%d, %d, %q), want (%d, %d, %q)",
+					page, offset, mode, tt.wantPage, tt.wantOffset, tt.wantMode)
+			}
+		})
+	}
+}
+
+func Test_PaginateMaxPage(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(policy DepthPolicy) *fiber.App {
+		app := fiber.New()
+		app.Use(New(Config{MaxPage: 100, MaxOffset: 5000, DepthPolicy: policy}))
+		app.Get("/", func(c fiber.Ctx) error {
+			pageInfo, ok := FromContext(c)
+			if !ok {
+				return fiber.ErrBadRequest
+			}
+			return c.JSON(pageInfo)
+		})
+		return app
+	}
+
+	t.Run("Clamp", func(t *testing.T) {
+		resp, err := newApp(DepthClamp).Test(httptest.NewRequest("GET", "/?page=50000", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result PageInfo
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Page != 100 {
+			t.Errorf("Page = %d, want 100", result.Page)
+		}
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		resp, err := newApp(DepthReject).Test(httptest.NewRequest("GET", "/?offset=10000", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusBadRequest)
+		}
+
+		var body map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body["error"] != ErrDepthExceeded.Error() {
+			t.Errorf("error = %q, want %q", body["error"], ErrDepthExceeded.Error())
+		}
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		resp, err := newApp(DepthCursor).Test(httptest.NewRequest("GET", "/?page=50000", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result PageInfo
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Mode != ModeCursor {
+			t.Errorf("Mode = %q, want %q", result.Mode, ModeCursor)
+		}
+		if result.Page != 0 || result.Cursor != "" {
+			t.Errorf("Page, Cursor = %d, %q, want 0, empty", result.Page, result.Cursor)
+		}
+	})
+}
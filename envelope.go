@@ -0,0 +1,26 @@
+package spindle
+
+// Envelope is a response body carrying a page of items with the PageInfo
+// it was served with. EnvelopeSchema describes it for OpenAPI.
+type Envelope struct {
+	Data     any       `json:"data"`
+	PageInfo *PageInfo `json:"page_info"`
+	Meta     *Meta     `json:"meta,omitempty"`
+}
+
+// Meta carries response metadata beside the page.
+type Meta struct {
+	// Warnings lists the parameters the request was served with after
+	// coercion, when Config.Transparent is enabled.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// NewEnvelope wraps a page of items. With Config.Transparent enabled, the
+// warnings of p are echoed in meta.warnings.
+func NewEnvelope(data any, p *PageInfo) Envelope {
+	envelope := Envelope{Data: data, PageInfo: p}
+	if p != nil && p.transparent && len(p.Warnings) > 0 {
+		envelope.Meta = &Meta{Warnings: p.Warnings}
+	}
+	return envelope
+}
@@ -0,0 +1,75 @@
+// Package esx renders a spindle PageInfo into the pagination part of an
+// Elasticsearch or OpenSearch search request body.
+package esx
+
+import (
+	"github.com/mutantkeyboard/spindle"
+	"github.com/mutantkeyboard/spindle/keyset"
+)
+
+// Body returns the sort, size and from or search_after fields of a search
+// request body, to be merged with the query before encoding it as JSON:
+//
+//	body, err := esx.Body(pageInfo)
+//	body["query"] = map[string]any{"match": map[string]any{"title": q}}
+//
+// Shallow pages use from and size. In cursor mode Limit+1 hits are
+// requested and search_after is taken from the cursor, so pages may go
+// past index.max_result_window. search_after needs a sort that is unique
+// per document, or hits with equal sort values are skipped or repeated;
+// set spindle.Config.Tiebreaker to a unique field such as "id".
+func Body(p *spindle.PageInfo) (map[string]any, error) {
+	sort := make([]map[string]string, 0, len(p.Sort))
+	for _, field := range p.Sort {
+		sort = append(sort, map[string]string{field.Field: string(field.Order)})
+	}
+	body := map[string]any{"sort": sort}
+
+	if !keyset.IsCursor(p) {
+		body["from"] = p.Start()
+		body["size"] = p.Limit
+		return body, nil
+	}
+
+	body["size"] = p.Limit + 1
+	if p.Cursor == "" {
+		return body, nil
+	}
+
+	columns, err := keyset.Columns(p)
+	if err != nil {
+		return nil, err
+	}
+	if columns == nil {
+		return nil, spindle.ErrInvalidCursor
+	}
+
+	after := make([]any, 0, len(columns))
+	for _, column := range columns {
+		after = append(after, column.Value)
+	}
+	body["search_after"] = after
+
+	return body, nil
+}
+
+// Trim drops the extra hit fetched in cursor mode and sets HasMore and
+// NextCursor from the sort values of the last hit kept. sortValues returns
+// the "sort" array Elasticsearch reports for a hit. Outside cursor mode
+// hits are returned unchanged.
+func Trim[T any](hits []T, p *spindle.PageInfo, sortValues func(hit T) []any) []T {
+	if !keyset.IsCursor(p) {
+		return hits
+	}
+
+	return spindle.TrimPage(hits, p, func(last T) map[string]any {
+		values := sortValues(last)
+		cursor := make(map[string]any, len(p.Sort))
+		for i, field := range p.Sort {
+			if i < len(values) {
+				cursor[field.Field] = values[i]
+			}
+		}
+		return cursor
+	})
+}
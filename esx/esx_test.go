@@ -0,0 +1,156 @@
+package esx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/mutantkeyboard/spindle"
+)
+
+func cursorFor(values map[string]any) string {
+	return (&spindle.PageInfo{}).SetNextCursor(values).NextCursor
+}
+
+func render(t *testing.T, body map[string]any) string {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestBody(t *testing.T) {
+	t.Parallel()
+
+	sort := []spindle.SortField{{Field: "created_at", Order: spindle.DESC}}
+	withID := []spindle.SortField{{Field: "created_at", Order: spindle.DESC}, {Field: "id", Order: spindle.DESC}}
+
+	tests := []struct {
+		name     string
+		pageInfo *spindle.PageInfo
+		expected string
+	}{
+		{
+			name:     "Page mode",
+			pageInfo: spindle.NewPageInfo(3, 20, 0, sort),
+			expected: `{"from":40,"size":20,"sort":[{"created_at":"desc"}]}`,
+		},
+		{
+			name:     "First cursor page",
+			pageInfo: &spindle.PageInfo{Limit: 10, Mode: spindle.ModeCursor, Sort: withID},
+			expected: `{"size":11,"sort":[{"created_at":"desc"},{"id":"desc"}]}`,
+		},
+		{
+			name: "Search after",
+			pageInfo: &spindle.PageInfo{
+				Limit:  10,
+				Mode:   spindle.ModeCursor,
+				Sort:   withID,
+				Cursor: cursorFor(map[string]any{"created_at": 1700000000000, "id": "b7"}),
+			},
+			expected: `{"search_after":[1700000000000,"b7"],"size":11,"sort":[{"created_at":"desc"},{"id":"desc"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body, err := Body(tt.pageInfo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := render(t, body); got != tt.expected {
+				t.Errorf("Body() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBodyConfigTiebreaker(t *testing.T) {
+	t.Parallel()
+
+	parser := spindle.NewParser(spindle.Config{
+		SortKey:      "sort",
+		AllowedSorts: []string{"created_at"},
+		Tiebreaker:   "id",
+		Mode:         spindle.ModeCursor,
+	})
+	p, err := parser.Parse(spindle.Input{Query: url.Values{"sort": {"-created_at"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := Body(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := render(t, body), `{"size":11,"sort":[{"created_at":"desc"},{"id":"desc"}]}`; got != expected {
+		t.Errorf("Body() = %s, want %s", got, expected)
+	}
+}
+
+func TestBodyCursorMismatch(t *testing.T) {
+	t.Parallel()
+
+	p := &spindle.PageInfo{
+		Limit:  10,
+		Mode:   spindle.ModeCursor,
+		Sort:   []spindle.SortField{{Field: "created_at", Order: spindle.DESC}, {Field: "id", Order: spindle.DESC}},
+		Cursor: cursorFor(map[string]any{"created_at": 1}),
+	}
+	if _, err := Body(p); !errors.Is(err, spindle.ErrCursorMismatch) {
+		t.Errorf("Body() error = %v, want %v", err, spindle.ErrCursorMismatch)
+	}
+}
+
+type hit struct {
+	ID   string
+	Sort []any
+}
+
+func TestTrim(t *testing.T) {
+	t.Parallel()
+
+	hits := []hit{
+		{ID: "a", Sort: []any{float64(3), "a"}},
+		{ID: "b", Sort: []any{float64(2), "b"}},
+		{ID: "c", Sort: []any{float64(1), "c"}},
+	}
+	sortValues := func(h hit) []any { return h.Sort }
+
+	t.Run("More hits", func(t *testing.T) {
+		sort := []spindle.SortField{{Field: "score", Order: spindle.DESC}, {Field: "id", Order: spindle.DESC}}
+		p := &spindle.PageInfo{Limit: 2, Mode: spindle.ModeCursor, Sort: sort}
+
+		got := Trim(hits, p, sortValues)
+		if len(got) != 2 || !p.HasMore {
+			t.Fatalf("Trim() = %d hits, HasMore %v, want 2 hits and HasMore", len(got), p.HasMore)
+		}
+
+		next := &spindle.PageInfo{Cursor: p.NextCursor}
+		expected := map[string]any{"score": float64(2), "id": "b"}
+		if values := next.CursorValues(); !reflect.DeepEqual(values, expected) {
+			t.Errorf("cursor = %v, want %v", values, expected)
+		}
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		p := &spindle.PageInfo{Limit: 5, Mode: spindle.ModeCursor}
+		if got := Trim(hits, p, sortValues); len(got) != 3 || p.HasMore || p.NextCursor != "" {
+			t.Errorf("Trim() = %d hits, HasMore %v, NextCursor %q", len(got), p.HasMore, p.NextCursor)
+		}
+	})
+
+	t.Run("Page mode", func(t *testing.T) {
+		p := spindle.NewPageInfo(1, 2, 0, nil)
+		if got := Trim(hits, p, sortValues); len(got) != 3 {
+			t.Errorf("Trim() = %d hits, want 3", len(got))
+		}
+	})
+}
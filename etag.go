@@ -0,0 +1,103 @@
+package spindle
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// errNoPageInfo is returned by the envelope helpers when the middleware
+// did not run for the request.
+var errNoPageInfo = errors.New("spindle: no PageInfo for the request, mount New or NewHTTP first")
+
+// ETag returns a weak ETag for the page p serves when the underlying data
+// is at version, such as the largest updated_at of the collection or a
+// change counter. Requests resolving to the same Key share the ETag until
+// the version changes.
+func (p *PageInfo) ETag(version string) string {
+	sum := sha256.Sum256([]byte(p.Key() + "\x00" + version))
+	return `W/"` + base64.RawURLEncoding.EncodeToString(sum[:12]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 prescribes for it.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// NotModified sets the ETag of p at version on a Fiber response and
+// reports whether the request's If-None-Match matches it, in which case
+// the response has been turned into a 304 Not Modified and the handler
+// should return without writing a body:
+//
+//	if spindle.NotModified(c, pageInfo, version) {
+//		return nil
+//	}
+func NotModified(c fiber.Ctx, p *PageInfo, version string) bool {
+	etag := p.ETag(version)
+	c.Set(fiber.HeaderETag, etag)
+	if !etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return false
+	}
+
+	c.Status(fiber.StatusNotModified)
+	c.Response().ResetBody()
+	return true
+}
+
+// NotModifiedHTTP is NotModified for net/http. On a match it writes the
+// 304 response itself.
+func NotModifiedHTTP(w http.ResponseWriter, r *http.Request, p *PageInfo, version string) bool {
+	etag := p.ETag(version)
+	w.Header().Set("ETag", etag)
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// SendEnvelope answers a Fiber request with data wrapped by NewEnvelope in
+// the PageInfo resolved by New. When version is not empty the response
+// carries an ETag, and a matching If-None-Match is answered with 304.
+func SendEnvelope(c fiber.Ctx, data any, version string) error {
+	pageInfo, ok := FromContext(c)
+	if !ok {
+		return errNoPageInfo
+	}
+	if version != "" && NotModified(c, pageInfo, version) {
+		return nil
+	}
+	return c.JSON(NewEnvelope(data, pageInfo))
+}
+
+// WriteEnvelope is SendEnvelope for net/http, using the PageInfo resolved
+// by NewHTTP. Nothing is written when the request has no PageInfo.
+func WriteEnvelope(w http.ResponseWriter, r *http.Request, data any, version string) error {
+	pageInfo, ok := FromStdContext(r.Context())
+	if !ok {
+		return errNoPageInfo
+	}
+	if version != "" && NotModifiedHTTP(w, r, pageInfo, version) {
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(NewEnvelope(data, pageInfo))
+}
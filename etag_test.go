@@ -0,0 +1,148 @@
+package spindle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestPageInfoETag(t *testing.T) {
+	t.Parallel()
+
+	page2 := &PageInfo{Mode: ModePage, Page: 2, Limit: 10}
+	etag := page2.ETag("v1")
+
+	if !strings.HasPrefix(etag, `W/"`) || !strings.HasSuffix(etag, `"`) {
+		t.Errorf("ETag() = %s, want a weak ETag", etag)
+	}
+	if other := (&PageInfo{Mode: ModePage, Page: 2, Limit: 10}).ETag("v1"); other != etag {
+		t.Errorf("ETag() = %s for the same page, want %s", other, etag)
+	}
+	if other := page2.ETag("v2"); other == etag {
+		t.Error("ETag() unchanged for a new version")
+	}
+	if other := (&PageInfo{Mode: ModePage, Page: 3, Limit: 10}).ETag("v1"); other == etag {
+		t.Error("ETag() unchanged for another page")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	t.Parallel()
+
+	etag := `W/"abc"`
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{"", false},
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"xyz", W/"abc"`, true},
+		{`"xyz"`, false},
+		{"*", true},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, etag); got != tt.expected {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func Test_PaginateSendEnvelope(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/feed", func(c fiber.Ctx) error {
+		return SendEnvelope(c, []string{"a", "b"}, "2026-10-19T10:00:00Z")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/feed", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != fiber.StatusOK || etag == "" {
+		t.Fatalf("status, ETag = %d, %q, want 200 and an ETag", resp.StatusCode, etag)
+	}
+	var envelope struct {
+		Data []string `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope.Data) != 2 {
+		t.Errorf("data = %v, want [a b]", envelope.Data)
+	}
+
+	req := httptest.NewRequest("GET", "/feed?page=1", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusNotModified {
+		t.Errorf("status = %d, want 304", resp.StatusCode)
+	}
+
+	req = httptest.NewRequest("GET", "/feed?page=2", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status = %d for another page, want 200", resp.StatusCode)
+	}
+}
+
+func TestSendEnvelopeWithoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		return SendEnvelope(c, nil, "")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", resp.StatusCode)
+	}
+}
+
+func TestNewHTTPWriteEnvelope(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHTTP()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := WriteEnvelope(w, r, []int{1}, "7"); err != nil {
+			t.Error(err)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || !strings.Contains(rec.Body.String(), `"data":[1]`) {
+		t.Fatalf("status, ETag, body = %d, %q, %s", rec.Code, etag, rec.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("status, body = %d, %q, want 304 and no body", rec.Code, rec.Body.String())
+	}
+
+	if err := WriteEnvelope(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), nil, ""); err == nil {
+		t.Error("WriteEnvelope() without NewHTTP = nil error")
+	}
+}
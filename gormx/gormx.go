@@ -0,0 +1,76 @@
+// Package gormx applies a spindle PageInfo to GORM queries.
+package gormx
+
+import (
+	"github.com/mutantkeyboard/spindle"
+	"github.com/mutantkeyboard/spindle/keyset"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Scope returns a GORM scope applying the sort, limit and offset, or keyset
+// predicate, of a PageInfo:
+//
+//	db.Model(&User{}).Scopes(gormx.Scope(pageInfo)).Find(&users)
+//
+// Sort fields are used as column names; map them with
+// spindle.SortRegistry.Columns first when they differ. In cursor mode the
+// cursor must hold a value for every sort field, and Limit+1 rows are
+// fetched so spindle.TrimPage can tell whether there is a next page.
+func Scope(p *spindle.PageInfo) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, field := range p.Sort {
+			db = db.Order(clause.OrderByColumn{
+				Column: clause.Column{Name: field.Field},
+				Desc:   field.Order == spindle.DESC,
+			})
+		}
+
+		if !keyset.IsCursor(p) {
+			return db.Limit(p.Limit).Offset(p.Start())
+		}
+
+		columns, err := keyset.Columns(p)
+		if err != nil {
+			db.AddError(err) //nolint:errcheck
+			return db
+		}
+		if len(columns) > 0 {
+			db = db.Where(predicate(columns))
+		}
+		return db.Limit(p.Limit + 1)
+	}
+}
+
+// predicate selects the rows after the cursor position:
+// a > ? OR (a = ? AND b > ?) OR ..., with < for descending columns.
+func predicate(columns []keyset.Column) clause.Expression {
+	ors := make([]clause.Expression, 0, len(columns))
+	for i, column := range columns {
+		ands := make([]clause.Expression, 0, i+1)
+		for _, prev := range columns[:i] {
+			ands = append(ands, clause.Eq{Column: clause.Column{Name: prev.Name}, Value: prev.Value})
+		}
+
+		name := clause.Column{Name: column.Name}
+		if column.Desc {
+			ands = append(ands, clause.Lt{Column: name, Value: column.Value})
+		} else {
+			ands = append(ands, clause.Gt{Column: name, Value: column.Value})
+		}
+		ors = append(ors, clause.And(ands...))
+	}
+	return clause.Or(ors...)
+}
+
+// Count runs a count query on db and records the result as the PageInfo
+// total. Pass the query before Scope is applied, as its limit and offset
+// would otherwise apply to the count.
+func Count(db *gorm.DB, p *spindle.PageInfo) error {
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return err
+	}
+	p.SetTotal(int(total))
+	return nil
+}
@@ -0,0 +1,172 @@
+package gormx
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/mutantkeyboard/spindle"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type article struct {
+	ID    int
+	Title string
+	Score int
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&article{}); err != nil {
+		t.Fatal(err)
+	}
+
+	articles := []article{
+		{ID: 1, Title: "e", Score: 3},
+		{ID: 2, Title: "d", Score: 1},
+		{ID: 3, Title: "c", Score: 3},
+		{ID: 4, Title: "b", Score: 2},
+		{ID: 5, Title: "a", Score: 3},
+	}
+	if err := db.Create(&articles).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func articleIDs(articles []article) []int {
+	ids := make([]int, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+func TestScopePage(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	p := spindle.NewPageInfo(2, 2, 0, []spindle.SortField{{Field: "title", Order: spindle.ASC}})
+
+	var articles []article
+	if err := db.Scopes(Scope(p)).Find(&articles).Error; err != nil {
+		t.Fatal(err)
+	}
+	if ids := articleIDs(articles); !reflect.DeepEqual(ids, []int{3, 2}) {
+		t.Errorf("ids = %v, want [3 2]", ids)
+	}
+}
+
+func TestScopeOffset(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	p := spindle.NewPageInfo(1, 10, 3, []spindle.SortField{{Field: "id", Order: spindle.DESC}})
+
+	var articles []article
+	if err := db.Scopes(Scope(p)).Find(&articles).Error; err != nil {
+		t.Fatal(err)
+	}
+	if ids := articleIDs(articles); !reflect.DeepEqual(ids, []int{2, 1}) {
+		t.Errorf("ids = %v, want [2 1]", ids)
+	}
+}
+
+func TestScopeCursor(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	// Mixed directions with ties on score
+	sort := []spindle.SortField{{Field: "score", Order: spindle.DESC}, {Field: "id", Order: spindle.ASC}}
+
+	var ids []int
+	cursor := ""
+	for range 5 {
+		p := &spindle.PageInfo{Limit: 2, Sort: sort, Mode: spindle.ModeCursor, Cursor: cursor}
+
+		var articles []article
+		if err := db.Scopes(Scope(p)).Find(&articles).Error; err != nil {
+			t.Fatal(err)
+		}
+		articles = spindle.TrimPage(articles, p, func(last article) map[string]any {
+			return map[string]any{"score": last.Score, "id": last.ID}
+		})
+		ids = append(ids, articleIDs(articles)...)
+
+		if !p.HasMore {
+			break
+		}
+		cursor = p.NextCursor
+	}
+
+	expected := []int{1, 3, 5, 4, 2}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("ids across pages = %v, want %v", ids, expected)
+	}
+}
+
+func TestScopeCursorMissingValue(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	cursor := (&spindle.PageInfo{}).SetNextCursor(map[string]any{"id": 2}).NextCursor
+	p := &spindle.PageInfo{
+		Limit:  2,
+		Sort:   []spindle.SortField{{Field: "title", Order: spindle.ASC}},
+		Mode:   spindle.ModeCursor,
+		Cursor: cursor,
+	}
+
+	var articles []article
+	err := db.Scopes(Scope(p)).Find(&articles).Error
+	if !errors.Is(err, spindle.ErrCursorMismatch) {
+		t.Errorf("error = %v, want %v", err, spindle.ErrCursorMismatch)
+	}
+}
+
+func TestScopeSQL(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	cursor := (&spindle.PageInfo{}).SetNextCursor(map[string]any{"score": 3, "id": 5}).NextCursor
+	p := &spindle.PageInfo{
+		Limit:  10,
+		Sort:   []spindle.SortField{{Field: "score", Order: spindle.DESC}, {Field: "id", Order: spindle.ASC}},
+		Mode:   spindle.ModeCursor,
+		Cursor: cursor,
+	}
+
+	var articles []article
+	stmt := db.Session(&gorm.Session{DryRun: true}).Scopes(Scope(p)).Find(&articles).Statement
+
+	expected := "SELECT * FROM `articles` WHERE (`score` < ? OR (`score` = ? AND `id` > ?)) ORDER BY `score` DESC,`id` LIMIT 11"
+	if sql := stmt.SQL.String(); sql != expected {
+		t.Errorf("SQL = %q, want %q", sql, expected)
+	}
+	if vars := stmt.Vars; !reflect.DeepEqual(vars, []any{int64(3), int64(3), int64(5)}) {
+		t.Errorf("Vars = %v, want [3 3 5]", vars)
+	}
+}
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	p := spindle.NewPageInfo(1, 2, 0, nil)
+	if err := Count(db.Model(&article{}).Where("score = ?", 3), p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Total != 3 {
+		t.Errorf("Total = %d, want 3", p.Total)
+	}
+}
@@ -0,0 +1,125 @@
+// Package keyset pairs the sort fields of a PageInfo with the values of its
+// cursor, for adapters that build keyset predicates. The gormx, sqlpage,
+// mongox and esx adapters are built on it, and so can adapters for other
+// stores.
+package keyset
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mutantkeyboard/spindle"
+)
+
+// Column is one column of a keyset: the sort field, its direction and the
+// cursor value to seek past.
+type Column struct {
+	Name  string
+	Desc  bool
+	Value any
+}
+
+// IsCursor reports whether p is served in cursor mode, either resolved as
+// such or carrying a cursor.
+func IsCursor(p *spindle.PageInfo) bool {
+	return p.Mode == spindle.ModeCursor || p.Cursor != ""
+}
+
+// Columns pairs each sort field with its cursor value. It returns nil when
+// there is no cursor, and an error wrapping spindle.ErrCursorMismatch when
+// the cursor lacks a value for one of the sort fields. Integral numbers are
+// returned as int64, so IDs beyond 2^53 keep their exact value, and other
+// numbers as float64.
+func Columns(p *spindle.PageInfo) ([]Column, error) {
+	values := cursorValues(p.Cursor)
+	if values == nil {
+		return nil, nil
+	}
+
+	columns := make([]Column, 0, len(p.Sort))
+	for _, field := range p.Sort {
+		value, ok := values[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: no value for sort field %q", spindle.ErrCursorMismatch, field.Field)
+		}
+		columns = append(columns, Column{Name: field.Field, Desc: field.Order == spindle.DESC, Value: value})
+	}
+	return columns, nil
+}
+
+// cursorValues decodes a cursor like spindle.PageInfo.CursorValues, but
+// with exact numbers. Returns nil if cursor is empty or invalid.
+func cursorValues(cursor string) map[string]any {
+	if cursor == "" {
+		return nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return nil
+	}
+
+	for key, value := range values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if n, err := number.Int64(); err == nil {
+			values[key] = n
+		} else if f, err := number.Float64(); err == nil {
+			values[key] = f
+		}
+	}
+	return values
+}
+
+// SQL renders the predicate selecting the rows after the cursor position:
+//
+//	(a < ? OR (a = ? AND b > ?))
+//
+// with < for descending and > for ascending columns. quote escapes column
+// names and placeholder returns the next bind parameter; args are returned
+// in placeholder order.
+func SQL(columns []Column, quote func(name string) string, placeholder func() string) (string, []any) {
+	var b strings.Builder
+	var args []any
+
+	b.WriteByte('(')
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteString(" OR (")
+		}
+		for _, prev := range columns[:i] {
+			b.WriteString(quote(prev.Name))
+			b.WriteString(" = ")
+			b.WriteString(placeholder())
+			b.WriteString(" AND ")
+			args = append(args, prev.Value)
+		}
+
+		b.WriteString(quote(column.Name))
+		if column.Desc {
+			b.WriteString(" < ")
+		} else {
+			b.WriteString(" > ")
+		}
+		b.WriteString(placeholder())
+		args = append(args, column.Value)
+
+		if i > 0 {
+			b.WriteByte(')')
+		}
+	}
+	b.WriteByte(')')
+
+	return b.String(), args
+}
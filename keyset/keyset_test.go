@@ -0,0 +1,123 @@
+package keyset
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/mutantkeyboard/spindle"
+)
+
+func TestIsCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pageInfo *spindle.PageInfo
+		expected bool
+	}{
+		{&spindle.PageInfo{Mode: spindle.ModeCursor}, true},
+		{&spindle.PageInfo{Mode: spindle.ModePage, Cursor: "eyJpZCI6MX0"}, true},
+		{&spindle.PageInfo{Mode: spindle.ModeOffset}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsCursor(tt.pageInfo); got != tt.expected {
+			t.Errorf("IsCursor(%+v) = %v, want %v", tt.pageInfo, got, tt.expected)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+
+	cursor := (&spindle.PageInfo{}).SetNextCursor(map[string]any{"created_at": "2026-01-01", "id": 7}).NextCursor
+	p := &spindle.PageInfo{
+		Cursor: cursor,
+		Sort:   []spindle.SortField{{Field: "created_at", Order: spindle.DESC}, {Field: "id", Order: spindle.ASC}},
+	}
+
+	columns, err := Columns(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Column{
+		{Name: "created_at", Desc: true, Value: "2026-01-01"},
+		{Name: "id", Desc: false, Value: int64(7)},
+	}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Columns() = %v, want %v", columns, expected)
+	}
+}
+
+func TestColumnsExactNumbers(t *testing.T) {
+	t.Parallel()
+
+	cursor := (&spindle.PageInfo{}).SetNextCursor(map[string]any{"id": int64(9007199254740993), "score": 2.5}).NextCursor
+	p := &spindle.PageInfo{
+		Cursor: cursor,
+		Sort:   []spindle.SortField{{Field: "score", Order: spindle.DESC}, {Field: "id", Order: spindle.ASC}},
+	}
+
+	columns, err := Columns(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Column{
+		{Name: "score", Desc: true, Value: 2.5},
+		{Name: "id", Desc: false, Value: int64(9007199254740993)},
+	}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Columns() = %v, want %v", columns, expected)
+	}
+}
+
+func TestColumnsWithoutCursor(t *testing.T) {
+	t.Parallel()
+
+	columns, err := Columns(&spindle.PageInfo{Sort: []spindle.SortField{{Field: "id"}}})
+	if err != nil || columns != nil {
+		t.Errorf("Columns() = %v, %v, want nil, nil", columns, err)
+	}
+}
+
+func TestColumnsMissingValue(t *testing.T) {
+	t.Parallel()
+
+	cursor := (&spindle.PageInfo{}).SetNextCursor(map[string]any{"id": 7}).NextCursor
+	p := &spindle.PageInfo{
+		Cursor: cursor,
+		Sort:   []spindle.SortField{{Field: "name", Order: spindle.ASC}},
+	}
+
+	if _, err := Columns(p); !errors.Is(err, spindle.ErrCursorMismatch) {
+		t.Errorf("Columns() error = %v, want %v", err, spindle.ErrCursorMismatch)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	t.Parallel()
+
+	columns := []Column{
+		{Name: "score", Desc: true, Value: 3},
+		{Name: "name", Desc: false, Value: "b"},
+		{Name: "id", Desc: false, Value: 5},
+	}
+
+	n := 0
+	placeholder := func() string {
+		n++
+		return "$" + strconv.Itoa(n)
+	}
+	quote := func(name string) string { return `"` + name + `"` }
+
+	sql, args := SQL(columns, quote, placeholder)
+
+	expected := `("score" < $1 OR ("score" = $2 AND "name" > $3) OR ("score" = $4 AND "name" = $5 AND "id" > $6))`
+	if sql != expected {
+		t.Errorf("SQL() = %s, want %s", sql, expected)
+	}
+	if !reflect.DeepEqual(args, []any{3, 3, "b", 3, "b", 5}) {
+		t.Errorf("SQL() args = %v, want [3 3 b 3 b 5]", args)
+	}
+}
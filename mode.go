@@ -0,0 +1,79 @@
+package spindle
+
+import "errors"
+
+// Mode selects how a request is paginated.
+type Mode string
+
+const (
+	// ModeAuto picks the mode from the parameters present, preferring
+	// cursor, then offset, then page.
+	ModeAuto Mode = "auto"
+	// ModePage paginates by page number.
+	ModePage Mode = "page"
+	// ModeOffset paginates by item offset.
+	ModeOffset Mode = "offset"
+	// ModeCursor paginates by opaque cursor.
+	ModeCursor Mode = "cursor"
+)
+
+// ErrConflictingParameters is returned in strict mode when a request mixes
+// parameters of different pagination modes, such as page and offset.
+var ErrConflictingParameters = errors.New("conflicting pagination parameters")
+
+// presence records which pagination parameters a request supplied.
+type presence struct {
+	cursor bool
+	page   bool
+	offset bool
+}
+
+// count returns how many pagination modes the request asked for.
+func (h presence) count() int {
+	n := 0
+	for _, ok := range []bool{h.cursor, h.page, h.offset} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+// is reports whether the request supplied the parameter of mode.
+func (h presence) is(mode Mode) bool {
+	switch mode {
+	case ModeCursor:
+		return h.cursor
+	case ModeOffset:
+		return h.offset
+	case ModePage:
+		return h.page
+	default:
+		return false
+	}
+}
+
+// resolveMode decides the mode a request is served in. Parameters that do
+// not belong to the resolved mode are ignored, or rejected when strict.
+func resolveMode(mode Mode, strict bool, has presence) (Mode, error) {
+	if strict && has.count() > 1 {
+		return "", ErrConflictingParameters
+	}
+
+	switch mode {
+	case ModePage, ModeOffset, ModeCursor:
+		if strict && has.count() == 1 && !has.is(mode) {
+			return "", ErrConflictingParameters
+		}
+		return mode, nil
+	}
+
+	switch {
+	case has.cursor:
+		return ModeCursor, nil
+	case has.offset:
+		return ModeOffset, nil
+	default:
+		return ModePage, nil
+	}
+}
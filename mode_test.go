@@ -0,0 +1,226 @@
+package spindle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestResolveMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mode     Mode
+		strict   bool
+		has      presence
+		expected Mode
+		err      error
+	}{
+		{"Auto with nothing", ModeAuto, false, presence{}, ModePage, nil},
+		{"Auto with page", ModeAuto, false, presence{page: true}, ModePage, nil},
+		{"Auto with offset", ModeAuto, false, presence{offset: true}, ModeOffset, nil},
+		{"Auto with cursor", ModeAuto, false, presence{cursor: true}, ModeCursor, nil},
+		{"Auto prefers offset over page", ModeAuto, false, presence{page: true, offset: true}, ModeOffset, nil},
+		{"Auto prefers cursor over page", ModeAuto, false, presence{cursor: true, page: true}, ModeCursor, nil},
+		{"Auto strict page and offset", ModeAuto, true, presence{page: true, offset: true}, "", ErrConflictingParameters},
+		{"Auto strict cursor and page", ModeAuto, true, presence{cursor: true, page: true}, "", ErrConflictingParameters},
+		{"Auto strict single", ModeAuto, true, presence{offset: true}, ModeOffset, nil},
+		{"Page ignores offset", ModePage, false, presence{offset: true}, ModePage, nil},
+		{"Page strict rejects offset", ModePage, true, presence{offset: true}, "", ErrConflictingParameters},
+		{"Offset strict accepts offset", ModeOffset, true, presence{offset: true}, ModeOffset, nil},
+		{"Cursor without cursor", ModeCursor, true, presence{}, ModeCursor, nil},
+		{"Cursor strict rejects page", ModeCursor, true, presence{page: true}, "", ErrConflictingParameters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, err := resolveMode(tt.mode, tt.strict, tt.has)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("resolveMode() error = %v, want %v", err, tt.err)
+			}
+			if mode != tt.expected {
+				t.Errorf("resolveMode() = %q, want %q", mode, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_PaginateModeRecorded(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{OffsetKey: "skip"}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, ok := FromContext(c)
+		if !ok {
+			return fiber.ErrBadRequest
+		}
+		return c.JSON(pageInfo)
+	})
+
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(`{"id":1}`))
+
+	testCases := []struct {
+		name   string
+		query  string
+		mode   Mode
+		offset int
+	}{
+		{"Page", "/?page=2", ModePage, 0},
+		{"Offset with custom key", "/?skip=30", ModeOffset, 30},
+		{"Default offset key ignored", "/?offset=30", ModePage, 0},
+		{"Cursor", "/?cursor=" + cursor, ModeCursor, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result PageInfo
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Mode != tc.mode {
+				t.Errorf("Mode = %q, want %q", result.Mode, tc.mode)
+			}
+			if result.Offset != tc.offset {
+				t.Errorf("Offset = %d, want %d", result.Offset, tc.offset)
+			}
+		})
+	}
+}
+
+func Test_PaginateStrictConflicts(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Strict: true}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		return c.JSON(pageInfo)
+	})
+
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(`{"id":1}`))
+
+	testCases := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"Page only", "/?page=3", 200},
+		{"Offset only", "/?offset=5", 200},
+		{"Page and offset", "/?page=3&offset=5", 400},
+		{"Cursor and page", "/?cursor=" + cursor + "&page=2", 400},
+		{"Cursor and offset", "/?cursor=" + cursor + "&offset=2", 400},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.status)
+			}
+		})
+	}
+}
+
+func Test_PaginateExplicitMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Page mode ignores offset", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(New(Config{Mode: ModePage}))
+		app.Get("/", func(c fiber.Ctx) error {
+			pageInfo, _ := FromContext(c)
+			return c.JSON(pageInfo)
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/?page=3&offset=5&limit=10", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result PageInfo
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Offset != 0 || result.Start() != 20 {
+			t.Errorf("Offset, Start() = %d, %d, want 0, 20", result.Offset, result.Start())
+		}
+	})
+
+	offsetTests := []struct {
+		name  string
+		cfg   Config
+		query string
+		start int
+	}{
+		{"Offset mode ignores page", Config{Mode: ModeOffset}, "/?page=3&limit=10", 0},
+		{"Offset mode with page and offset", Config{Mode: ModeOffset}, "/?page=3&offset=15&limit=10", 15},
+		{"Explicit zero offset", Config{}, "/?page=3&offset=0&limit=10", 0},
+	}
+	for _, tt := range offsetTests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(New(tt.cfg))
+			app.Get("/", func(c fiber.Ctx) error {
+				pageInfo, _ := FromContext(c)
+				return c.JSON(fiber.Map{"mode": pageInfo.Mode, "page": pageInfo.Page, "start": pageInfo.Start()})
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result struct {
+				Mode  Mode `json:"mode"`
+				Page  int  `json:"page"`
+				Start int  `json:"start"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Mode != ModeOffset || result.Start != tt.start || result.Page != 1 {
+				t.Errorf("Mode, Start(), Page = %q, %d, %d, want %q, %d, 1",
+					result.Mode, result.Start, result.Page, ModeOffset, tt.start)
+			}
+		})
+	}
+
+	t.Run("Cursor mode first page", func(t *testing.T) {
+		app := fiber.New()
+		app.Use(New(Config{Mode: ModeCursor}))
+		app.Get("/", func(c fiber.Ctx) error {
+			pageInfo, _ := FromContext(c)
+			return c.JSON(pageInfo)
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/?limit=5", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result PageInfo
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Mode != ModeCursor {
+			t.Errorf("Mode = %q, want %q", result.Mode, ModeCursor)
+		}
+		if result.Cursor != "" {
+			t.Errorf("Cursor = %q, want empty on the first page", result.Cursor)
+		}
+	})
+}
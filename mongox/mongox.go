@@ -0,0 +1,110 @@
+// Package mongox converts a spindle PageInfo into MongoDB find options and
+// keyset filters.
+package mongox
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mutantkeyboard/spindle"
+	"github.com/mutantkeyboard/spindle/keyset"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ValueFunc converts a cursor value before it is compared against field.
+// Cursor values come back from JSON as strings, int64s, float64s and bools, so
+// fields stored as ObjectIDs or dates need converting.
+type ValueFunc func(field string, value any) (any, error)
+
+// ObjectIDs returns a ValueFunc converting the hex strings of the named
+// fields to ObjectIDs. Other fields are left as they are.
+func ObjectIDs(fields ...string) ValueFunc {
+	return func(field string, value any) (any, error) {
+		if !slices.Contains(fields, field) {
+			return value, nil
+		}
+		hex, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an ObjectID", spindle.ErrInvalidCursor, field)
+		}
+		id, err := bson.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is not an ObjectID", spindle.ErrInvalidCursor, field)
+		}
+		return id, nil
+	}
+}
+
+// Sort returns the sort document of a PageInfo, 1 for ascending and -1 for
+// descending fields.
+func Sort(p *spindle.PageInfo) bson.D {
+	sort := make(bson.D, 0, len(p.Sort))
+	for _, field := range p.Sort {
+		direction := 1
+		if field.Order == spindle.DESC {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: field.Field, Value: direction})
+	}
+	return sort
+}
+
+// FindOptions returns find options with the sort, skip and limit of a
+// PageInfo. In cursor mode nothing is skipped and Limit+1 documents are
+// fetched so spindle.TrimPage can tell whether there is a next page.
+func FindOptions(p *spindle.PageInfo) *options.FindOptionsBuilder {
+	opts := options.Find().SetSort(Sort(p))
+	if keyset.IsCursor(p) {
+		return opts.SetLimit(int64(p.Limit + 1))
+	}
+	return opts.SetSkip(int64(p.Start())).SetLimit(int64(p.Limit))
+}
+
+// Filter returns the keyset filter selecting the documents after the
+// cursor:
+//
+//	{"$or": [{a: {"$lt": x}}, {a: x, b: {"$gt": y}}]}
+//
+// with $lt for descending and $gt for ascending fields. It returns an empty
+// filter without a cursor. convert may be nil. Combine the result with the
+// query's own filter using $and.
+func Filter(p *spindle.PageInfo, convert ValueFunc) (bson.D, error) {
+	if !keyset.IsCursor(p) {
+		return bson.D{}, nil
+	}
+
+	columns, err := keyset.Columns(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return bson.D{}, nil
+	}
+
+	if convert != nil {
+		for i := range columns {
+			columns[i].Value, err = convert(columns[i].Name, columns[i].Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	branches := make(bson.A, 0, len(columns))
+	for i, column := range columns {
+		branch := make(bson.D, 0, i+1)
+		for _, prev := range columns[:i] {
+			branch = append(branch, bson.E{Key: prev.Name, Value: prev.Value})
+		}
+
+		operator := "$gt"
+		if column.Desc {
+			operator = "$lt"
+		}
+		branch = append(branch, bson.E{Key: column.Name, Value: bson.D{{Key: operator, Value: column.Value}}})
+		branches = append(branches, branch)
+	}
+
+	return bson.D{{Key: "$or", Value: branches}}, nil
+}
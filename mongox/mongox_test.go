@@ -0,0 +1,159 @@
+package mongox
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mutantkeyboard/spindle"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func cursorFor(values map[string]any) string {
+	return (&spindle.PageInfo{}).SetNextCursor(values).NextCursor
+}
+
+func findOptions(t *testing.T, builder *options.FindOptionsBuilder) options.FindOptions {
+	t.Helper()
+
+	var opts options.FindOptions
+	for _, set := range builder.Opts {
+		if err := set(&opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return opts
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	p := &spindle.PageInfo{Sort: []spindle.SortField{
+		{Field: "created_at", Order: spindle.DESC},
+		{Field: "_id", Order: spindle.ASC},
+	}}
+
+	expected := bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+	if sort := Sort(p); !reflect.DeepEqual(sort, expected) {
+		t.Errorf("Sort() = %v, want %v", sort, expected)
+	}
+}
+
+func TestFindOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Page mode", func(t *testing.T) {
+		opts := findOptions(t, FindOptions(spindle.NewPageInfo(3, 20, 0, nil)))
+		if opts.Skip == nil || *opts.Skip != 40 {
+			t.Errorf("Skip = %v, want 40", opts.Skip)
+		}
+		if opts.Limit == nil || *opts.Limit != 20 {
+			t.Errorf("Limit = %v, want 20", opts.Limit)
+		}
+	})
+
+	t.Run("Cursor mode", func(t *testing.T) {
+		p := &spindle.PageInfo{Limit: 20, Mode: spindle.ModeCursor, Sort: []spindle.SortField{{Field: "_id", Order: spindle.ASC}}}
+		opts := findOptions(t, FindOptions(p))
+		if opts.Skip != nil {
+			t.Errorf("Skip = %v, want nil", *opts.Skip)
+		}
+		if opts.Limit == nil || *opts.Limit != 21 {
+			t.Errorf("Limit = %v, want 21", opts.Limit)
+		}
+		if !reflect.DeepEqual(opts.Sort, bson.D{{Key: "_id", Value: 1}}) {
+			t.Errorf("Sort = %v, want {_id: 1}", opts.Sort)
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	p := &spindle.PageInfo{
+		Limit:  10,
+		Mode:   spindle.ModeCursor,
+		Cursor: cursorFor(map[string]any{"score": 3, "name": "b", "_id": 5}),
+		Sort: []spindle.SortField{
+			{Field: "score", Order: spindle.DESC},
+			{Field: "name", Order: spindle.ASC},
+			{Field: "_id", Order: spindle.DESC},
+		},
+	}
+
+	filter, err := Filter(p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "score", Value: bson.D{{Key: "$lt", Value: int64(3)}}}},
+		bson.D{
+			{Key: "score", Value: int64(3)},
+			{Key: "name", Value: bson.D{{Key: "$gt", Value: "b"}}},
+		},
+		bson.D{
+			{Key: "score", Value: int64(3)},
+			{Key: "name", Value: "b"},
+			{Key: "_id", Value: bson.D{{Key: "$lt", Value: int64(5)}}},
+		},
+	}}}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("Filter() = %v, want %v", filter, expected)
+	}
+}
+
+func TestFilterWithoutCursor(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []*spindle.PageInfo{
+		spindle.NewPageInfo(2, 10, 0, nil),
+		{Limit: 10, Mode: spindle.ModeCursor},
+	} {
+		filter, err := Filter(p, nil)
+		if err != nil || len(filter) != 0 {
+			t.Errorf("Filter() = %v, %v, want empty filter", filter, err)
+		}
+	}
+}
+
+func TestFilterObjectIDs(t *testing.T) {
+	t.Parallel()
+
+	id := bson.NewObjectID()
+	p := &spindle.PageInfo{
+		Limit:  10,
+		Cursor: cursorFor(map[string]any{"_id": id}),
+		Sort:   []spindle.SortField{{Field: "_id", Order: spindle.ASC}},
+	}
+
+	filter, err := Filter(p, ObjectIDs("_id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: id}}}},
+	}}}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("Filter() = %v, want %v", filter, expected)
+	}
+
+	p.Cursor = cursorFor(map[string]any{"_id": "not-an-id"})
+	if _, err := Filter(p, ObjectIDs("_id")); !errors.Is(err, spindle.ErrInvalidCursor) {
+		t.Errorf("Filter() error = %v, want %v", err, spindle.ErrInvalidCursor)
+	}
+}
+
+func TestFilterMissingValue(t *testing.T) {
+	t.Parallel()
+
+	p := &spindle.PageInfo{
+		Limit:  10,
+		Cursor: cursorFor(map[string]any{"_id": 1}),
+		Sort:   []spindle.SortField{{Field: "name", Order: spindle.ASC}},
+	}
+	if _, err := Filter(p, nil); !errors.Is(err, spindle.ErrCursorMismatch) {
+		t.Errorf("Filter() error = %v, want %v", err, spindle.ErrCursorMismatch)
+	}
+}
@@ -0,0 +1,166 @@
+package spindle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+)
+
+// NewHTTP creates net/http middleware applying the same rules as New, for
+// use with the standard library, chi and other net/http routers. The
+// PageInfo is stored in the request context; see FromStdContext.
+// Config.Next, Routes, MaxLimitFunc and DefaultLimitFunc are Fiber-specific
+// and ignored; use a Parser with Input.MaxLimit for per-request limits.
+func NewHTTP(config ...Config) func(http.Handler) http.Handler {
+	parser := NewParser(config...)
+	cfg := parser.cfg
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.RangeHeader {
+				w.Header().Set("Accept-Ranges", cfg.RangeUnit)
+			}
+
+			pageInfo, err := parser.Parse(httpInput(r, cfg))
+			if err != nil {
+				if cfg.Observer != nil {
+					cfg.Observer.Reject(r.Context(), err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}) //nolint:errcheck
+				return
+			}
+
+			if cfg.Observer != nil {
+				cfg.Observer.Observe(r.Context(), pageInfo)
+			}
+			if cfg.Transparent {
+				for _, header := range warningHeaders(pageInfo.Warnings) {
+					w.Header().Add("Warning", header)
+				}
+			}
+			if pageInfo.hasCanonical {
+				if redirectsToCanonical(r.Method, r.URL.RawQuery, pageInfo) {
+					http.Redirect(w, r, pageInfo.CanonicalURL(r.URL.Path), http.StatusMovedPermanently)
+					return
+				}
+				w.Header().Add("Link", canonicalLink(requestBaseURL(r)+r.URL.Path, pageInfo))
+			}
+
+			r = r.WithContext(NewContext(r.Context(), pageInfo))
+			if pageInfo.ranged {
+				w = &rangeWriter{ResponseWriter: w, unit: cfg.RangeUnit, pageInfo: pageInfo}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewContext returns a copy of ctx carrying the PageInfo.
+func NewContext(ctx context.Context, pageInfo *PageInfo) context.Context {
+	return context.WithValue(ctx, pageInfoKey, pageInfo)
+}
+
+// FromStdContext returns the PageInfo stored by NewHTTP or NewContext.
+func FromStdContext(ctx context.Context) (*PageInfo, bool) {
+	if pageInfo, ok := ctx.Value(pageInfoKey).(*PageInfo); ok {
+		return pageInfo, true
+	}
+	return nil, false
+}
+
+// defaultMaxMemory is the multipart memory limit net/http uses for
+// PostFormValue.
+const defaultMaxMemory = 32 << 20
+
+// httpInput builds the Parser input for a net/http request. A JSON body is
+// read only when one of the sources needs it and it fits Config.BodyLimit,
+// and is restored for the handler afterwards.
+func httpInput(r *http.Request, cfg Config) Input {
+	in := Input{
+		Context: r.Context(),
+		Query:   r.URL.Query(),
+		Header:  r.Header,
+	}
+
+	if slices.Contains(cfg.Sources, SourceBody) && r.Body != nil {
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+			data, err := io.ReadAll(io.LimitReader(r.Body, int64(cfg.BodyLimit)+1))
+			if err == nil && len(data) <= cfg.BodyLimit {
+				in.Body = JSONBody(data)
+			}
+			// The handler reads what was buffered, then the rest of the body.
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(data), r.Body), r.Body}
+		}
+	}
+	if slices.Contains(cfg.Sources, SourceForm) {
+		// PostForm is filled from multipart bodies too; other content
+		// types leave it empty.
+		_ = r.ParseMultipartForm(defaultMaxMemory)
+		in.Form = r.PostForm
+	}
+	return in
+}
+
+// rangeWriter adds Content-Range to the response of a Range request once
+// the handler starts writing it.
+type rangeWriter struct {
+	http.ResponseWriter
+	unit     string
+	pageInfo *PageInfo
+
+	wroteHeader bool
+	discard     bool
+}
+
+func (w *rangeWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if value, rangeStatus, ok := contentRange(w.unit, w.pageInfo); ok {
+		w.Header().Set("Content-Range", value)
+		switch {
+		case rangeStatus == http.StatusRequestedRangeNotSatisfiable:
+			w.Header().Del("Content-Length")
+			w.discard = true
+			status = rangeStatus
+		case status == http.StatusOK:
+			status = rangeStatus
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *rangeWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *rangeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// requestBaseURL returns the scheme and host a request was sent to.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
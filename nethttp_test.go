@@ -0,0 +1,206 @@
+package spindle
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newHTTPHandler(config ...Config) http.Handler {
+	return NewHTTP(config...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageInfo, ok := FromStdContext(r.Context())
+		if !ok {
+			http.Error(w, "missing page info", http.StatusInternalServerError)
+			return
+		}
+		if total := r.URL.Query().Get("total"); total != "" {
+			pageInfo.SetTotal(len(total))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pageInfo) //nolint:errcheck
+	}))
+}
+
+func TestNewHTTP(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	newHTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/users?page=2&limit=20", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var result PageInfo
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Page != 2 || result.Limit != 20 {
+		t.Errorf("Page, Limit = %d, %d, want 2, 20", result.Page, result.Limit)
+	}
+}
+
+func TestNewHTTPInvalidCursor(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	newHTTPHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/users?cursor=not-valid!!!", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["error"] != ErrInvalidCursor.Error() {
+		t.Errorf("error = %q, want %q", body["error"], ErrInvalidCursor.Error())
+	}
+}
+
+func TestNewHTTPJSONBody(t *testing.T) {
+	t.Parallel()
+
+	var handlerBody string
+	handler := NewHTTP(Config{Sources: []Source{SourceBody}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		handlerBody = string(data)
+
+		pageInfo, _ := FromStdContext(r.Context())
+		json.NewEncoder(w).Encode(pageInfo) //nolint:errcheck
+	}))
+
+	body := `{"page": 3, "limit": 5}`
+	req := httptest.NewRequest("POST", "/search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var result PageInfo
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Page != 3 || result.Limit != 5 {
+		t.Errorf("Page, Limit = %d, %d, want 3, 5", result.Page, result.Limit)
+	}
+	if handlerBody != body {
+		t.Errorf("handler body = %q, want %q", handlerBody, body)
+	}
+}
+
+func TestNewHTTPBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	var handlerBody string
+	handler := NewHTTP(Config{Sources: []Source{SourceBody}, BodyLimit: 16})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		handlerBody = string(data)
+
+		pageInfo, _ := FromStdContext(r.Context())
+		json.NewEncoder(w).Encode(pageInfo) //nolint:errcheck
+	}))
+
+	body := `{"page": 3, "limit": 5, "query": "title:go"}`
+	req := httptest.NewRequest("POST", "/search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var result PageInfo
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Page != 1 || result.Limit != 10 {
+		t.Errorf("Page, Limit = %d, %d, want the defaults for a body over the limit", result.Page, result.Limit)
+	}
+	if handlerBody != body {
+		t.Errorf("handler body = %q, want %q", handlerBody, body)
+	}
+}
+
+func TestNewHTTPForm(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest("POST", "/search", strings.NewReader("page=7"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	newHTTPHandler(Config{Sources: []Source{SourceForm}}).ServeHTTP(rec, req)
+
+	var result PageInfo
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Page != 7 {
+		t.Errorf("Page = %d, want 7", result.Page)
+	}
+}
+
+func TestNewHTTPRangeHeader(t *testing.T) {
+	t.Parallel()
+
+	handler := newHTTPHandler(Config{RangeHeader: true})
+
+	testCases := []struct {
+		name         string
+		query        string
+		status       int
+		contentRange string
+		emptyBody    bool
+	}{
+		// The handler sets a total equal to the length of the total parameter
+		{"Partial", "/?total=" + strings.Repeat("x", 50), http.StatusPartialContent, "items 0-9/50", false},
+		{"Past the end", "/?total=xxxx", http.StatusRequestedRangeNotSatisfiable, "items */4", true},
+		{"No total", "/", http.StatusOK, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.query, nil)
+			rangeHeader := "items=0-9"
+			if tc.emptyBody {
+				rangeHeader = "items=10-19"
+			}
+			req.Header.Set("Range", rangeHeader)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Errorf("status = %d, want %d", rec.Code, tc.status)
+			}
+			if got := rec.Header().Get("Content-Range"); got != tc.contentRange {
+				t.Errorf("Content-Range = %q, want %q", got, tc.contentRange)
+			}
+			if got := rec.Header().Get("Accept-Ranges"); got != "items" {
+				t.Errorf("Accept-Ranges = %q, want %q", got, "items")
+			}
+			if tc.emptyBody && rec.Body.Len() != 0 {
+				t.Errorf("body = %q, want empty", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestFromStdContext(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := FromStdContext(context.Background()); ok {
+		t.Error("FromStdContext() ok = true, want false for an empty context")
+	}
+
+	pageInfo := NewPageInfo(1, 10, 0, nil)
+	got, ok := FromStdContext(NewContext(context.Background(), pageInfo))
+	if !ok || got != pageInfo {
+		t.Errorf("FromStdContext() = %v, %v, want %v, true", got, ok, pageInfo)
+	}
+}
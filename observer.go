@@ -0,0 +1,63 @@
+package spindle
+
+import (
+	"context"
+	"errors"
+)
+
+// Observer records how clients paginate, for metrics or tracing. Its
+// methods are called on the request path and must be safe for concurrent
+// use.
+type Observer interface {
+	// Observe is called with each PageInfo the middleware resolves, before
+	// the handler runs.
+	Observe(ctx context.Context, p *PageInfo)
+
+	// Reject is called with the error of each request rejected with 400.
+	Reject(ctx context.Context, err error)
+}
+
+// MultiObserver returns an Observer telling each of observers in turn,
+// such as one recording metrics and one annotating traces.
+func MultiObserver(observers ...Observer) Observer {
+	return multiObserver(append([]Observer(nil), observers...))
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) Observe(ctx context.Context, p *PageInfo) {
+	for _, o := range m {
+		o.Observe(ctx, p)
+	}
+}
+
+func (m multiObserver) Reject(ctx context.Context, err error) {
+	for _, o := range m {
+		o.Reject(ctx, err)
+	}
+}
+
+// RejectReason returns a short label for an error returned by Parse, such
+// as "invalid_cursor", for use as a metric attribute.
+func RejectReason(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidCursor):
+		return "invalid_cursor"
+	case errors.Is(err, ErrCursorMismatch):
+		return "cursor_mismatch"
+	case errors.Is(err, ErrInvalidLimit):
+		return "invalid_limit"
+	case errors.Is(err, ErrConflictingParameters):
+		return "conflicting_parameters"
+	case errors.Is(err, ErrDepthExceeded):
+		return "depth_exceeded"
+	case errors.Is(err, ErrInvalidSort):
+		return "invalid_sort"
+	case errors.Is(err, ErrInvalidSnapshot):
+		return "invalid_snapshot"
+	case errors.Is(err, ErrSnapshotRequired):
+		return "snapshot_required"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,112 @@
+package spindle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type recordingObserver struct {
+	mu       sync.Mutex
+	observed []*PageInfo
+	rejected []error
+}
+
+func (o *recordingObserver) Observe(_ context.Context, p *PageInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.observed = append(o.observed, p)
+}
+
+func (o *recordingObserver) Reject(_ context.Context, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.rejected = append(o.rejected, err)
+}
+
+func TestRejectReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{ErrInvalidCursor, "invalid_cursor"},
+		{ErrCursorMismatch, "cursor_mismatch"},
+		{fmt.Errorf("%w: limit", ErrInvalidLimit), "invalid_limit"},
+		{ErrConflictingParameters, "conflicting_parameters"},
+		{ErrDepthExceeded, "depth_exceeded"},
+		{fmt.Errorf("%w: name", ErrInvalidSort), "invalid_sort"},
+		{errors.New("boom"), "other"},
+	}
+
+	for _, tt := range tests {
+		if got := RejectReason(tt.err); got != tt.expected {
+			t.Errorf("RejectReason(%v) = %q, want %q", tt.err, got, tt.expected)
+		}
+	}
+}
+
+func Test_PaginateObserver(t *testing.T) {
+	t.Parallel()
+
+	observer := &recordingObserver{}
+	app := fiber.New()
+	app.Use(New(Config{Observer: observer}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, url := range []string{"/?page=2", "/?cursor=bm90IGpzb24"} {
+		if _, err := app.Test(httptest.NewRequest("GET", url, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(observer.observed) != 1 || observer.observed[0].Page != 2 {
+		t.Errorf("observed = %v, want one PageInfo for page 2", observer.observed)
+	}
+	if len(observer.rejected) != 1 || !errors.Is(observer.rejected[0], ErrInvalidCursor) {
+		t.Errorf("rejected = %v, want [%v]", observer.rejected, ErrInvalidCursor)
+	}
+}
+
+func TestNewHTTPObserver(t *testing.T) {
+	t.Parallel()
+
+	observer := &recordingObserver{}
+	handler := NewHTTP(Config{Observer: observer})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, url := range []string{"/?offset=20", "/?cursor=bm90IGpzb24"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+	}
+
+	if len(observer.observed) != 1 || observer.observed[0].Mode != ModeOffset {
+		t.Errorf("observed = %v, want one PageInfo in offset mode", observer.observed)
+	}
+	if len(observer.rejected) != 1 || !errors.Is(observer.rejected[0], ErrInvalidCursor) {
+		t.Errorf("rejected = %v, want [%v]", observer.rejected, ErrInvalidCursor)
+	}
+}
+
+func TestMultiObserver(t *testing.T) {
+	t.Parallel()
+
+	first, second := &recordingObserver{}, &recordingObserver{}
+	observer := MultiObserver(first, second)
+
+	observer.Observe(t.Context(), NewPageInfo(1, 10, 0, nil))
+	observer.Reject(t.Context(), ErrInvalidCursor)
+
+	for _, o := range []*recordingObserver{first, second} {
+		if len(o.observed) != 1 || len(o.rejected) != 1 {
+			t.Errorf("observed, rejected = %d, %d, want 1, 1", len(o.observed), len(o.rejected))
+		}
+	}
+}
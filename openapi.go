@@ -0,0 +1,222 @@
+package spindle
+
+import (
+	"slices"
+	"strings"
+)
+
+// OpenAPIParameter is an OpenAPI 3 parameter object.
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Style       string         `json:"style,omitempty"`
+	Explode     *bool          `json:"explode,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of an OpenAPI 3 schema object used to
+// describe pagination parameters and responses.
+type OpenAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Enum        []string                  `json:"enum,omitempty"`
+	Default     any                       `json:"default,omitempty"`
+	Minimum     *int                      `json:"minimum,omitempty"`
+	Maximum     *int                      `json:"maximum,omitempty"`
+	MaxItems    *int                      `json:"maxItems,omitempty"`
+	Items       *OpenAPISchema            `json:"items,omitempty"`
+	Properties  map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+}
+
+// OpenAPIParameters returns the parameters a paginated endpoint accepts
+// under config: page and offset unless the mode rules them out, limit
+// bounded by MaxLimit, sort with an enum built from AllowedSorts, the
+// cursor, and the snapshot when enabled. Parameters are described in the
+// query string, or in headers when Sources reads headers but not the
+// query. Body and form sources are left to the request body schema.
+func OpenAPIParameters(config ...Config) []OpenAPIParameter {
+	cfg := NewParser(config...).cfg
+
+	in := ""
+	switch {
+	case slices.Contains(cfg.Sources, SourceQuery):
+		in = "query"
+	case slices.Contains(cfg.Sources, SourceHeader):
+		in = "header"
+	default:
+		return nil
+	}
+
+	var params []OpenAPIParameter
+	if cfg.Mode != ModeCursor && cfg.Mode != ModeOffset {
+		page := &OpenAPISchema{Type: "integer", Minimum: intPtr(1), Default: cfg.DefaultPage}
+		if cfg.MaxPage > 0 && cfg.DepthPolicy == DepthReject {
+			page.Maximum = intPtr(cfg.MaxPage)
+		}
+		params = append(params, OpenAPIParameter{
+			Name: cfg.PageKey, In: in, Description: "Page number, starting at 1.", Schema: page,
+		})
+	}
+	if cfg.Mode != ModeCursor && cfg.Mode != ModePage {
+		offset := &OpenAPISchema{Type: "integer", Minimum: intPtr(0)}
+		if cfg.MaxOffset > 0 && cfg.DepthPolicy == DepthReject {
+			offset.Maximum = intPtr(cfg.MaxOffset)
+		}
+		params = append(params, OpenAPIParameter{
+			Name: cfg.OffsetKey, In: in, Description: "Number of items to skip.", Schema: offset,
+		})
+	}
+
+	// A limit of 0 is served with the default. The maximum and default
+	// are left out when they are decided per request.
+	limit := &OpenAPISchema{Type: "integer", Minimum: intPtr(0)}
+	if cfg.MaxLimitFunc == nil {
+		limit.Maximum = intPtr(MaxLimit)
+	}
+	if cfg.DefaultLimitFunc == nil {
+		limit.Default = cfg.DefaultLimit
+	}
+	params = append(params, OpenAPIParameter{
+		Name:        cfg.LimitKey,
+		In:          in,
+		Description: "Items per page. 0 means the default.",
+		Schema:      limit,
+	})
+
+	if cfg.SortKey != "" {
+		params = append(params, sortParameter(cfg, in))
+	}
+
+	if cfg.Mode != ModePage && cfg.Mode != ModeOffset {
+		params = append(params, OpenAPIParameter{
+			Name:        cfg.CursorKey,
+			In:          in,
+			Description: "Opaque cursor returned as next_cursor by the previous page.",
+			Schema:      &OpenAPISchema{Type: "string"},
+		})
+	}
+
+	if cfg.Snapshot && cfg.Mode != ModeCursor {
+		params = append(params, OpenAPIParameter{
+			Name:        cfg.SnapshotKey,
+			In:          in,
+			Description: "Opaque snapshot returned by the first page, keeping later pages consistent.",
+			Schema:      &OpenAPISchema{Type: "string"},
+		})
+	}
+
+	return params
+}
+
+// sortParameter describes the sort key as a comma-separated array of
+// allowed fields, each optionally prefixed with "-". The default is left
+// out when it is not one of them, so it stays valid against the enum.
+func sortParameter(cfg Config, in string) OpenAPIParameter {
+	enum := make([]string, 0, 2*len(cfg.AllowedSorts))
+	for _, field := range cfg.AllowedSorts {
+		enum = append(enum, field, "-"+field)
+	}
+
+	schema := &OpenAPISchema{
+		Type:  "array",
+		Items: &OpenAPISchema{Type: "string", Enum: enum},
+	}
+	if defaults := strings.Split(cfg.DefaultSort, ","); !slices.ContainsFunc(defaults, func(field string) bool {
+		return !slices.Contains(enum, field)
+	}) {
+		schema.Default = defaults
+	}
+	if cfg.MaxSortFields > 0 {
+		schema.MaxItems = intPtr(cfg.MaxSortFields)
+	}
+
+	explode := false
+	return OpenAPIParameter{
+		Name:        cfg.SortKey,
+		In:          in,
+		Description: "Comma-separated sort fields. Prefix a field with - for descending order.",
+		Style:       "form",
+		Explode:     &explode,
+		Schema:      schema,
+	}
+}
+
+// PageInfoSchema returns the schema of a PageInfo as encoded to JSON.
+func PageInfoSchema() *OpenAPISchema {
+	integer := func(description string) *OpenAPISchema {
+		return &OpenAPISchema{Type: "integer", Description: description}
+	}
+	str := func(description string) *OpenAPISchema {
+		return &OpenAPISchema{Type: "string", Description: description}
+	}
+
+	return &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"page":   integer("Page number."),
+			"limit":  integer("Items per page."),
+			"offset": integer("Number of items skipped."),
+			"sort": {
+				Type: "array",
+				Items: &OpenAPISchema{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"Field": str("Sort field."),
+						"Order": {Type: "string", Enum: []string{string(ASC), string(DESC)}},
+					},
+					Required: []string{"Field", "Order"},
+				},
+			},
+			"mode": {
+				Type:        "string",
+				Description: "Pagination mode the request was served with.",
+				Enum:        []string{string(ModePage), string(ModeOffset), string(ModeCursor)},
+			},
+			"cursor":      str("Cursor the page was requested with."),
+			"has_more":    {Type: "boolean", Description: "Whether another page follows."},
+			"next_cursor": str("Cursor of the next page."),
+			"total":       integer("Total number of items, when counted."),
+			"snapshot":    str("Snapshot to send with later pages."),
+		},
+		Required: []string{"page", "limit", "offset", "sort"},
+	}
+}
+
+// EnvelopeSchema returns the schema of an Envelope whose data holds items
+// described by item.
+func EnvelopeSchema(item *OpenAPISchema) *OpenAPISchema {
+	return &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"data":      {Type: "array", Items: item},
+			"page_info": PageInfoSchema(),
+			"meta": {
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					"warnings": {
+						Type:        "array",
+						Description: "Parameters the request was served with after coercion.",
+						Items: &OpenAPISchema{
+							Type: "object",
+							Properties: map[string]*OpenAPISchema{
+								"param":     {Type: "string"},
+								"original":  {Type: "string"},
+								"effective": {Type: "string"},
+								"message":   {Type: "string"},
+							},
+							Required: []string{"param", "original", "message"},
+						},
+					},
+				},
+			},
+		},
+		Required: []string{"data", "page_info"},
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
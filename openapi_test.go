@@ -0,0 +1,172 @@
+package spindle
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func parameterNames(params []OpenAPIParameter) []string {
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		names = append(names, param.Name)
+	}
+	return names
+}
+
+func TestOpenAPIParameters(t *testing.T) {
+	t.Parallel()
+
+	params := OpenAPIParameters(Config{
+		SortKey:       "sort",
+		DefaultSort:   "-created_at",
+		AllowedSorts:  []string{"created_at", "name"},
+		MaxSortFields: 2,
+		DefaultLimit:  25,
+	})
+
+	if names := parameterNames(params); !slices.Equal(names, []string{"page", "offset", "limit", "sort", "cursor"}) {
+		t.Fatalf("names = %v", names)
+	}
+	for _, param := range params {
+		if param.In != "query" {
+			t.Errorf("%s: In = %q, want query", param.Name, param.In)
+		}
+	}
+
+	limit := params[2].Schema
+	if *limit.Minimum != 0 || *limit.Maximum != MaxLimit || limit.Default != 25 {
+		t.Errorf("limit schema = %+v", limit)
+	}
+
+	sort := params[3]
+	if sort.Style != "form" || sort.Explode == nil || *sort.Explode {
+		t.Errorf("sort style = %q, explode = %v, want form and false", sort.Style, sort.Explode)
+	}
+	expectedEnum := []string{"created_at", "-created_at", "name", "-name"}
+	if !slices.Equal(sort.Schema.Items.Enum, expectedEnum) {
+		t.Errorf("sort enum = %v, want %v", sort.Schema.Items.Enum, expectedEnum)
+	}
+	if *sort.Schema.MaxItems != 2 || !reflect.DeepEqual(sort.Schema.Default, []string{"-created_at"}) {
+		t.Errorf("sort schema = %+v", sort.Schema)
+	}
+}
+
+func TestOpenAPIParametersModes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected []string
+	}{
+		{"Page", Config{Mode: ModePage}, []string{"page", "limit"}},
+		{"Offset", Config{Mode: ModeOffset}, []string{"offset", "limit"}},
+		{"Cursor", Config{Mode: ModeCursor}, []string{"limit", "cursor"}},
+		{"AIP-158", ConfigAIP158, []string{"page_size", "page_token"}},
+		{"Headers", Config{Mode: ModeCursor, Sources: []Source{SourceHeader}}, []string{"limit", "cursor"}},
+		{"Body only", Config{Sources: []Source{SourceBody}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if names := parameterNames(OpenAPIParameters(tt.cfg)); !slices.Equal(names, tt.expected) {
+				t.Errorf("names = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOpenAPIParametersPerRequestLimits(t *testing.T) {
+	t.Parallel()
+
+	params := OpenAPIParameters(Config{
+		Mode:             ModeCursor,
+		MaxLimitFunc:     func(fiber.Ctx) int { return 500 },
+		DefaultLimitFunc: func(fiber.Ctx) int { return 50 },
+	})
+	if limit := params[0].Schema; limit.Maximum != nil || limit.Default != nil {
+		t.Errorf("limit schema = %+v, want no maximum or default", limit)
+	}
+}
+
+func TestOpenAPIParametersSortDefault(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected any
+	}{
+		{"Allowed", Config{SortKey: "sort", AllowedSorts: []string{"name"}, DefaultSort: "-name"}, []string{"-name"}},
+		{"Implicit id not allowed", Config{SortKey: "sort", AllowedSorts: []string{"name"}}, nil},
+		{"No allowed sorts", Config{SortKey: "sort", DefaultSort: "name"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sort := sortParameter(NewParser(tt.cfg).cfg, "query")
+			if !reflect.DeepEqual(sort.Schema.Default, tt.expected) {
+				t.Errorf("sort default = %v, want %v", sort.Schema.Default, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOpenAPIParametersDepth(t *testing.T) {
+	t.Parallel()
+
+	clamped := OpenAPIParameters(Config{MaxPage: 10})
+	if clamped[0].Schema.Maximum != nil {
+		t.Errorf("clamped page maximum = %d, want none", *clamped[0].Schema.Maximum)
+	}
+
+	rejected := OpenAPIParameters(Config{MaxPage: 10, MaxOffset: 500, DepthPolicy: DepthReject})
+	if *rejected[0].Schema.Maximum != 10 || *rejected[1].Schema.Maximum != 500 {
+		t.Errorf("page, offset maximum = %d, %d, want 10, 500", *rejected[0].Schema.Maximum, *rejected[1].Schema.Maximum)
+	}
+}
+
+// TestPageInfoSchema checks the schema lists every field a PageInfo encodes.
+func TestPageInfoSchema(t *testing.T) {
+	t.Parallel()
+
+	p := NewPageInfo(1, 10, 0, []SortField{{Field: "id", Order: ASC}})
+	p.Mode = ModeCursor
+	p.Cursor = "a"
+	p.SetNextCursor(map[string]any{"id": 1}).SetTotal(3)
+
+	data, err := json.Marshal(Envelope{Data: []int{1}, PageInfo: p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope map[string]any
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := EnvelopeSchema(&OpenAPISchema{Type: "integer"})
+	for key := range envelope {
+		if schema.Properties[key] == nil {
+			t.Errorf("envelope key %q missing from schema", key)
+		}
+	}
+
+	pageInfoSchema := schema.Properties["page_info"]
+	for key := range envelope["page_info"].(map[string]any) {
+		if pageInfoSchema.Properties[key] == nil {
+			t.Errorf("page_info key %q missing from schema", key)
+		}
+	}
+	sortItem := envelope["page_info"].(map[string]any)["sort"].([]any)[0].(map[string]any)
+	for key := range sortItem {
+		if pageInfoSchema.Properties["sort"].Items.Properties[key] == nil {
+			t.Errorf("sort key %q missing from schema", key)
+		}
+	}
+}
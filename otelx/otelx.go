@@ -0,0 +1,108 @@
+// Package otelx records spindle pagination usage with OpenTelemetry.
+package otelx
+
+import (
+	"context"
+
+	"github.com/mutantkeyboard/spindle"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Attribute keys used on metrics and spans.
+const (
+	ModeKey      = attribute.Key("pagination.mode")
+	SortFieldKey = attribute.Key("pagination.sort.field")
+	SortOrderKey = attribute.Key("pagination.sort.order")
+	ReasonKey    = attribute.Key("pagination.reject.reason")
+)
+
+// Metrics is a spindle.Observer recording:
+//
+//	pagination.requests     counter of resolved requests, by mode
+//	pagination.limit        histogram of Limit, by mode
+//	pagination.page         histogram of Page in page mode
+//	pagination.offset       histogram of Offset in offset mode
+//	pagination.sort_fields  counter of sort fields used, by field and order
+//	pagination.rejections   counter of requests rejected with 400, by reason
+type Metrics struct {
+	requests   metric.Int64Counter
+	limit      metric.Int64Histogram
+	page       metric.Int64Histogram
+	offset     metric.Int64Histogram
+	sorts      metric.Int64Counter
+	rejections metric.Int64Counter
+}
+
+var _ spindle.Observer = (*Metrics)(nil)
+
+// NewMetrics creates the instruments of a Metrics observer on meter:
+//
+//	metrics, err := otelx.NewMetrics(otel.Meter("spindle"))
+//	app.Use(spindle.New(spindle.Config{Observer: metrics}))
+func NewMetrics(meter metric.Meter) (*Metrics, error) {
+	var m Metrics
+	var err error
+
+	if m.requests, err = meter.Int64Counter("pagination.requests",
+		metric.WithDescription("Paginated requests by resolved mode."),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	if m.limit, err = meter.Int64Histogram("pagination.limit",
+		metric.WithDescription("Resolved page size."),
+		metric.WithUnit("{item}"),
+		metric.WithExplicitBucketBoundaries(1, 5, 10, 20, 25, 50, 100, 250, 500, 1000)); err != nil {
+		return nil, err
+	}
+	if m.page, err = meter.Int64Histogram("pagination.page",
+		metric.WithDescription("Requested page number in page mode."),
+		metric.WithUnit("{page}"),
+		metric.WithExplicitBucketBoundaries(1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048)); err != nil {
+		return nil, err
+	}
+	if m.offset, err = meter.Int64Histogram("pagination.offset",
+		metric.WithDescription("Requested offset in offset mode."),
+		metric.WithUnit("{item}"),
+		metric.WithExplicitBucketBoundaries(10, 40, 160, 640, 2560, 10240, 40960, 163840, 655360)); err != nil {
+		return nil, err
+	}
+	if m.sorts, err = meter.Int64Counter("pagination.sort_fields",
+		metric.WithDescription("Sort fields used, by field and order."),
+		metric.WithUnit("{field}")); err != nil {
+		return nil, err
+	}
+	if m.rejections, err = meter.Int64Counter("pagination.rejections",
+		metric.WithDescription("Requests rejected with 400, by reason."),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Observe records a resolved PageInfo.
+func (m *Metrics) Observe(ctx context.Context, p *spindle.PageInfo) {
+	mode := metric.WithAttributes(ModeKey.String(string(p.Mode)))
+	m.requests.Add(ctx, 1, mode)
+	m.limit.Record(ctx, int64(p.Limit), mode)
+
+	switch p.Mode {
+	case spindle.ModePage:
+		m.page.Record(ctx, int64(p.Page))
+	case spindle.ModeOffset:
+		m.offset.Record(ctx, int64(p.Start()))
+	}
+
+	for _, field := range p.Sort {
+		m.sorts.Add(ctx, 1, metric.WithAttributes(
+			SortFieldKey.String(field.Field),
+			SortOrderKey.String(string(field.Order)),
+		))
+	}
+}
+
+// Reject records a rejected request.
+func (m *Metrics) Reject(ctx context.Context, err error) {
+	m.rejections.Add(ctx, 1, metric.WithAttributes(ReasonKey.String(spindle.RejectReason(err))))
+}
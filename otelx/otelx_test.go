@@ -0,0 +1,84 @@
+package otelx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mutantkeyboard/spindle"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	result := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			result[m.Name] = m.Data
+		}
+	}
+	return result
+}
+
+func sum(data metricdata.Aggregation, attrs ...attribute.KeyValue) int64 {
+	want := attribute.NewSet(attrs...)
+	for _, point := range data.(metricdata.Sum[int64]).DataPoints {
+		if point.Attributes.Equals(&want) {
+			return point.Value
+		}
+	}
+	return 0
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	metrics, err := NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	page := spindle.NewPageInfo(3, 20, 0, []spindle.SortField{{Field: "name", Order: spindle.DESC}})
+	page.Mode = spindle.ModePage
+	offset := spindle.NewPageInfo(1, 10, 40, nil)
+	offset.Mode = spindle.ModeOffset
+
+	metrics.Observe(ctx, page)
+	metrics.Observe(ctx, page)
+	metrics.Observe(ctx, offset)
+	metrics.Reject(ctx, spindle.ErrInvalidCursor)
+	metrics.Reject(ctx, errors.New("other"))
+
+	data := collect(t, reader)
+
+	if got := sum(data["pagination.requests"], ModeKey.String("page")); got != 2 {
+		t.Errorf("page requests = %d, want 2", got)
+	}
+	if got := sum(data["pagination.sort_fields"], SortFieldKey.String("name"), SortOrderKey.String("desc")); got != 2 {
+		t.Errorf("name desc = %d, want 2", got)
+	}
+	if got := sum(data["pagination.rejections"], ReasonKey.String("invalid_cursor")); got != 1 {
+		t.Errorf("invalid cursors = %d, want 1", got)
+	}
+
+	pages := data["pagination.page"].(metricdata.Histogram[int64]).DataPoints
+	if len(pages) != 1 || pages[0].Count != 2 || pages[0].Sum != 6 {
+		t.Errorf("page histogram = %+v, want 2 observations summing to 6", pages)
+	}
+	offsets := data["pagination.offset"].(metricdata.Histogram[int64]).DataPoints
+	if len(offsets) != 1 || offsets[0].Sum != 40 {
+		t.Errorf("offset histogram = %+v, want one observation of 40", offsets)
+	}
+	if limits := data["pagination.limit"].(metricdata.Histogram[int64]).DataPoints; len(limits) != 2 {
+		t.Errorf("limit series = %d, want 2", len(limits))
+	}
+}
@@ -0,0 +1,74 @@
+package otelx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mutantkeyboard/spindle"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys used on spans, next to ModeKey and ReasonKey.
+const (
+	LimitKey  = attribute.Key("pagination.limit")
+	PageKey   = attribute.Key("pagination.page")
+	OffsetKey = attribute.Key("pagination.offset")
+	SortKey   = attribute.Key("pagination.sort")
+	CursorKey = attribute.Key("pagination.cursor")
+)
+
+// Spans is a spindle.Observer annotating the span active in the request
+// context with the resolved pagination: mode, limit, the page in page mode
+// or the offset in offset mode, the sort as it would appear in the query
+// string, such as "-created_at,id", and whether a cursor was supplied.
+// Rejected requests get the reject reason; they are client errors, so the
+// span status is left alone. Nothing is recorded without a recording span,
+// so tracing middleware must run before spindle's.
+type Spans struct{}
+
+var _ spindle.Observer = Spans{}
+
+// Observe annotates the active span with p.
+func (Spans) Observe(ctx context.Context, p *spindle.PageInfo) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.SetAttributes(
+		ModeKey.String(string(p.Mode)),
+		LimitKey.Int(p.Limit),
+		SortKey.String(sortString(p.Sort)),
+		CursorKey.Bool(p.Cursor != ""),
+	)
+	switch p.Mode {
+	case spindle.ModePage:
+		span.SetAttributes(PageKey.Int(p.Page))
+	case spindle.ModeOffset:
+		span.SetAttributes(OffsetKey.Int(p.Start()))
+	}
+}
+
+// Reject records the reject reason on the active span.
+func (Spans) Reject(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.SetAttributes(ReasonKey.String(spindle.RejectReason(err)))
+}
+
+// sortString renders sort fields the way the sort query spells them.
+func sortString(sort []spindle.SortField) string {
+	fields := make([]string, 0, len(sort))
+	for _, field := range sort {
+		if field.Order == spindle.DESC {
+			fields = append(fields, "-"+field.Field)
+		} else {
+			fields = append(fields, field.Field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,123 @@
+package otelx
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/mutantkeyboard/spindle"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func tracedApp(recorder *tracetest.SpanRecorder) *fiber.App {
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		ctx, span := tracer.Start(c.Context(), "request")
+		defer span.End()
+		c.SetContext(ctx)
+		return c.Next()
+	})
+	app.Use(spindle.New(spindle.Config{
+		SortKey:      "sort",
+		AllowedSorts: []string{"id", "created_at"},
+		Observer:     Spans{},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestSpans(t *testing.T) {
+	t.Parallel()
+
+	cursor := spindle.NewPageInfo(1, 10, 0, nil)
+	cursor.SetNextCursor(map[string]any{"id": 7})
+
+	testCases := []struct {
+		name     string
+		url      string
+		expected []attribute.KeyValue
+	}{
+		{"Page", "/?page=3&limit=20&sort=-created_at,id", []attribute.KeyValue{
+			ModeKey.String("page"),
+			LimitKey.Int(20),
+			PageKey.Int(3),
+			SortKey.String("-created_at,id"),
+			CursorKey.Bool(false),
+		}},
+		{"Offset", "/?offset=40&limit=20&sort=id", []attribute.KeyValue{
+			ModeKey.String("offset"),
+			LimitKey.Int(20),
+			OffsetKey.Int(40),
+			SortKey.String("id"),
+			CursorKey.Bool(false),
+		}},
+		{"Cursor", "/?sort=id&cursor=" + cursor.NextCursor, []attribute.KeyValue{
+			ModeKey.String("cursor"),
+			LimitKey.Int(10),
+			SortKey.String("id"),
+			CursorKey.Bool(true),
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+			app := tracedApp(recorder)
+
+			if _, err := app.Test(httptest.NewRequest("GET", tc.url, nil)); err != nil {
+				t.Fatal(err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("spans = %d, want 1", len(spans))
+			}
+
+			got := attribute.NewSet(spans[0].Attributes()...)
+			expected := attribute.NewSet(tc.expected...)
+			if !got.Equals(&expected) {
+				t.Errorf("attributes = %v, want %v", got.ToSlice(), expected.ToSlice())
+			}
+		})
+	}
+}
+
+func TestSpansReject(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	app := tracedApp(recorder)
+
+	if _, err := app.Test(httptest.NewRequest("GET", "/?cursor=bm90IGpzb24", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	span := recorder.Ended()[0]
+	attrs := attribute.NewSet(span.Attributes()...)
+	reason, ok := attrs.Value(ReasonKey)
+	if !ok || reason.AsString() != "invalid_cursor" {
+		t.Errorf("%s = %v, want invalid_cursor", ReasonKey, reason.AsString())
+	}
+	// A rejected request is a client error, not a failed span.
+	if span.Status().Code != codes.Unset {
+		t.Errorf("status = %v, want %v", span.Status().Code, codes.Unset)
+	}
+}
+
+func TestSpansWithoutSpan(t *testing.T) {
+	t.Parallel()
+
+	// Without an active span there is nothing to annotate, and nothing
+	// must panic.
+	Spans{}.Observe(t.Context(), spindle.NewPageInfo(1, 10, 0, nil))
+	Spans{}.Reject(t.Context(), spindle.ErrInvalidCursor)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"maps"
 )
 
 // SortOrder represents sort order.
@@ -38,9 +39,71 @@ type PageInfo struct {
 	Limit      int         `json:"limit"`
 	Offset     int         `json:"offset"`
 	Sort       []SortField `json:"sort"`
+	Mode       Mode        `json:"mode,omitempty"`
 	Cursor     string      `json:"cursor,omitempty"`
 	HasMore    bool        `json:"has_more,omitempty"`
 	NextCursor string      `json:"next_cursor,omitempty"`
+	Total      int         `json:"total,omitempty"`
+
+	// Snapshot is the as-of value page and offset requests are served at
+	// when Config.Snapshot is enabled, such as a timestamp to filter on
+	// with WHERE created_at <= snapshot. SnapshotToken is its opaque form,
+	// sent back by clients and carried by generated page URLs.
+	Snapshot      string `json:"-"`
+	SnapshotToken string `json:"snapshot,omitempty"`
+
+	// Warnings lists the parameters the parser changed instead of
+	// rejecting, for handlers to surface to clients.
+	Warnings []Warning `json:"-"`
+
+	keys        queryKeys
+	binding     string
+	boundQuery  string
+	hasTotal    bool
+	ranged      bool
+	transparent bool
+
+	snapshotSecret  []byte
+	carriedSnapshot bool
+
+	canonical    string
+	hasCanonical bool
+	clamped      bool
+}
+
+// cursorBindingKey is the reserved cursor entry carrying the request
+// binding when Config.BindCursor is enabled.
+const cursorBindingKey = "_bind"
+
+// queryKeys holds the query keys a PageInfo was parsed with, so generated
+// URLs round-trip through the same middleware configuration.
+type queryKeys struct {
+	page        string
+	offset      string
+	limit       string
+	sort        string
+	cursor      string
+	cursorParam string
+	snapshot    string
+}
+
+func (k queryKeys) withDefaults() queryKeys {
+	if k.page == "" {
+		k.page = ConfigDefault.PageKey
+	}
+	if k.offset == "" {
+		k.offset = ConfigDefault.OffsetKey
+	}
+	if k.limit == "" {
+		k.limit = ConfigDefault.LimitKey
+	}
+	if k.cursor == "" {
+		k.cursor = ConfigDefault.CursorKey
+	}
+	if k.snapshot == "" {
+		k.snapshot = ConfigDefault.SnapshotKey
+	}
+	return k
 }
 
 // NewPageInfo creates a new PageInfo.
@@ -53,9 +116,10 @@ func NewPageInfo(page, limit, offset int, sort []SortField) *PageInfo {
 	}
 }
 
-// Start returns the start index based on page/limit or offset.
+// Start returns the start index based on page/limit or offset. In offset
+// mode the offset is used even when it is 0.
 func (p *PageInfo) Start() int {
-	if p.Offset > 0 {
+	if p.Offset > 0 || p.Mode == ModeOffset {
 		return p.Offset
 	}
 	return (p.Page - 1) * p.Limit
@@ -67,27 +131,72 @@ func (p *PageInfo) SortBy(field string, order SortOrder) *PageInfo {
 	return p
 }
 
-// NextPageURL returns the URL for the next page.
+// NextPageURL returns the URL for the next page. In offset mode it
+// carries the offset of the next page, so unaligned offsets do not
+// overlap.
 func (p *PageInfo) NextPageURL(baseURL string) string {
-	return fmt.Sprintf("%s?page=%d&limit=%d", baseURL, p.Page+1, p.Limit)
+	k := p.keys.withDefaults()
+	if p.Mode == ModeOffset {
+		return fmt.Sprintf("%s?%s=%d&%s=%d", baseURL, k.offset, p.Start()+p.Limit, k.limit, p.Limit) + p.snapshotParam(k)
+	}
+	return fmt.Sprintf("%s?%s=%d&%s=%d", baseURL, k.page, p.Page+1, k.limit, p.Limit) + p.snapshotParam(k)
 }
 
 // PreviousPageURL returns the URL for the previous page.
-// Returns empty string if on page 1.
+// Returns empty string if on page 1, or at offset 0 in offset mode.
 func (p *PageInfo) PreviousPageURL(baseURL string) string {
+	k := p.keys.withDefaults()
+	if p.Mode == ModeOffset {
+		if p.Start() == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s?%s=%d&%s=%d", baseURL, k.offset, max(p.Start()-p.Limit, 0), k.limit, p.Limit) + p.snapshotParam(k)
+	}
 	if p.Page > 1 {
-		return fmt.Sprintf("%s?page=%d&limit=%d", baseURL, p.Page-1, p.Limit)
+		return fmt.Sprintf("%s?%s=%d&%s=%d", baseURL, k.page, p.Page-1, k.limit, p.Limit) + p.snapshotParam(k)
 	}
 	return ""
 }
 
+// snapshotParam returns the query parameter carrying the snapshot token to
+// the next request, if any.
+func (p *PageInfo) snapshotParam(k queryKeys) string {
+	if p.SnapshotToken == "" {
+		return ""
+	}
+	return "&" + k.snapshot + "=" + p.SnapshotToken
+}
+
 // NextCursorURL returns the URL for the next cursor page.
-// Returns empty string if HasMore is false.
+// Returns empty string if HasMore is false. With Config.BindCursor the
+// query parameters the cursor is bound to are carried over; body and form
+// fields cannot be, so clients sending them use NextPageToken instead.
 func (p *PageInfo) NextCursorURL(baseURL string) string {
 	if !p.HasMore {
 		return ""
 	}
-	return fmt.Sprintf("%s?cursor=%s&limit=%d", baseURL, p.NextCursor, p.Limit)
+	k := p.keys.withDefaults()
+	next := fmt.Sprintf("%s?%s=%s&%s=%d", baseURL, k.cursor, p.NextCursor, k.limit, p.Limit)
+	if p.boundQuery != "" {
+		next += "&" + p.boundQuery
+	}
+	return next
+}
+
+// NextPageToken returns the AIP-158 next_page_token.
+// Returns empty string if HasMore is false, marking the last page.
+func (p *PageInfo) NextPageToken() string {
+	if !p.HasMore {
+		return ""
+	}
+	return p.NextCursor
+}
+
+// SetTotal records the total number of items across all pages. Chainable.
+func (p *PageInfo) SetTotal(total int) *PageInfo {
+	p.Total = max(total, 0)
+	p.hasTotal = true
+	return p
 }
 
 // CursorValues decodes the opaque cursor into a key-value map.
@@ -106,6 +215,7 @@ func (p *PageInfo) CursorValues() map[string]any {
 	if err := json.Unmarshal(data, &values); err != nil {
 		return nil
 	}
+	delete(values, cursorBindingKey)
 
 	return values
 }
@@ -113,6 +223,13 @@ func (p *PageInfo) CursorValues() map[string]any {
 // SetNextCursor encodes a key-value map into an opaque cursor token
 // and sets both NextCursor and HasMore on the PageInfo. Chainable.
 func (p *PageInfo) SetNextCursor(values map[string]any) *PageInfo {
+	if p.binding != "" {
+		bound := make(map[string]any, len(values)+1)
+		maps.Copy(bound, values)
+		bound[cursorBindingKey] = p.binding
+		values = bound
+	}
+
 	data, err := json.Marshal(values)
 	if err != nil {
 		return p
@@ -1,6 +1,8 @@
 package spindle
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math"
 	"testing"
@@ -269,3 +271,109 @@ func TestSetNextCursorChainable(t *testing.T) {
 		t.Error("SetNextCursor should return the same PageInfo for chaining")
 	}
 }
+
+func TestNextPageToken(t *testing.T) {
+	t.Parallel()
+
+	p := &PageInfo{Limit: 10}
+	if token := p.NextPageToken(); token != "" {
+		t.Errorf("NextPageToken() = %q, want empty string on last page", token)
+	}
+
+	p.SetNextCursor(map[string]any{"id": float64(7)})
+	if token := p.NextPageToken(); token != p.NextCursor {
+		t.Errorf("NextPageToken() = %q, want %q", token, p.NextCursor)
+	}
+}
+
+func TestSetNextCursorBinding(t *testing.T) {
+	t.Parallel()
+
+	p := &PageInfo{Limit: 10, binding: "abc"}
+	p.SetNextCursor(map[string]any{"id": float64(7)})
+
+	data, err := base64.RawURLEncoding.DecodeString(p.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw[cursorBindingKey] != "abc" {
+		t.Errorf("raw[%s] = %v, want %q", cursorBindingKey, raw[cursorBindingKey], "abc")
+	}
+
+	// The binding is internal and never surfaces to handlers
+	decoded := (&PageInfo{Cursor: p.NextCursor}).CursorValues()
+	if _, ok := decoded[cursorBindingKey]; ok {
+		t.Errorf("CursorValues() = %v, want binding stripped", decoded)
+	}
+	if decoded["id"] != float64(7) {
+		t.Errorf("decoded[id] = %v, want 7", decoded["id"])
+	}
+}
+
+func TestURLsUseParsedKeys(t *testing.T) {
+	t.Parallel()
+
+	p := &PageInfo{Page: 2, Limit: 5, keys: queryKeys{page: "p", limit: "page_size", cursor: "page_token"}}
+	if got := p.NextPageURL("/items"); got != "/items?p=3&page_size=5" {
+		t.Errorf("NextPageURL() = %q, want %q", got, "/items?p=3&page_size=5")
+	}
+	if got := p.PreviousPageURL("/items"); got != "/items?p=1&page_size=5" {
+		t.Errorf("PreviousPageURL() = %q, want %q", got, "/items?p=1&page_size=5")
+	}
+
+	p.SetNextCursor(map[string]any{"id": float64(1)})
+	want := "/items?page_token=" + p.NextCursor + "&page_size=5"
+	if got := p.NextCursorURL("/items"); got != want {
+		t.Errorf("NextCursorURL() = %q, want %q", got, want)
+	}
+}
+
+func TestOffsetModeURLs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		offset   int
+		next     string
+		previous string
+	}{
+		{0, "/items?offset=10&limit=10", ""},
+		{5, "/items?offset=15&limit=10", "/items?offset=0&limit=10"},
+		{25, "/items?offset=35&limit=10", "/items?offset=15&limit=10"},
+	}
+
+	for _, tt := range tests {
+		p := &PageInfo{Page: 1, Limit: 10, Offset: tt.offset, Mode: ModeOffset}
+		if got := p.NextPageURL("/items"); got != tt.next {
+			t.Errorf("offset %d: NextPageURL() = %q, want %q", tt.offset, got, tt.next)
+		}
+		if got := p.PreviousPageURL("/items"); got != tt.previous {
+			t.Errorf("offset %d: PreviousPageURL() = %q, want %q", tt.offset, got, tt.previous)
+		}
+	}
+}
+
+func TestSetTotal(t *testing.T) {
+	t.Parallel()
+
+	p := &PageInfo{Limit: 10}
+	result := p.SetTotal(42)
+
+	if result != p {
+		t.Error("SetTotal should return the same PageInfo for chaining")
+	}
+	if p.Total != 42 {
+		t.Errorf("Total = %d, want 42", p.Total)
+	}
+	if !p.hasTotal {
+		t.Error("hasTotal = false, want true after SetTotal")
+	}
+
+	p.SetTotal(-5)
+	if p.Total != 0 {
+		t.Errorf("Total = %d, want 0 for negative input", p.Total)
+	}
+}
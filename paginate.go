@@ -1,8 +1,7 @@
 package spindle
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"net/url"
 	"slices"
 	"strings"
 
@@ -18,55 +17,53 @@ const MaxLimit = 100
 
 // New creates a new pagination middleware handler.
 func New(config ...Config) fiber.Handler {
-	cfg := configDefault(config...)
-	if cfg.DefaultSort == "" {
-		cfg.DefaultSort = "id"
-	}
+	base := NewParser(config...)
+	routes := newRouteTable(base.cfg)
 
 	return func(c fiber.Ctx) error {
-		if cfg.Next != nil && cfg.Next(c) {
-			return c.Next()
+		parser := base
+		if route := routes.lookup(c); route != nil {
+			parser = route
 		}
+		cfg := parser.cfg
 
-		limit := fiber.Query(c, cfg.LimitKey, cfg.DefaultLimit)
-		if limit < 1 {
-			limit = cfg.DefaultLimit
-		}
-		if limit > MaxLimit {
-			limit = MaxLimit
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
 		}
 
-		sorts := parseSortQuery(c.Query(cfg.SortKey), cfg.AllowedSorts, cfg.DefaultSort)
-
-		cursorRaw := c.Query(cfg.CursorKey)
-		if cursorRaw == "" && cfg.CursorParam != "" {
-			cursorRaw = c.Query(cfg.CursorParam)
+		if cfg.RangeHeader {
+			c.Set(fiber.HeaderAcceptRanges, cfg.RangeUnit)
 		}
 
-		if cursorRaw != "" {
-			data, err := base64.RawURLEncoding.DecodeString(cursorRaw)
-			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid cursor"})
+		pageInfo, err := parser.Parse(fiberInput(c, cfg))
+		if err != nil {
+			if cfg.Observer != nil {
+				cfg.Observer.Reject(c.Context(), err)
 			}
-			var obj map[string]any
-			if err := json.Unmarshal(data, &obj); err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid cursor"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if cfg.Observer != nil {
+			cfg.Observer.Observe(c.Context(), pageInfo)
+		}
+		if cfg.Transparent {
+			for _, header := range warningHeaders(pageInfo.Warnings) {
+				c.Response().Header.Add(fiber.HeaderWarning, header)
 			}
+		}
+		if redirected, err := writeCanonical(c, pageInfo); redirected || err != nil {
+			return err
+		}
 
-			pageInfo := &PageInfo{
-				Limit:  limit,
-				Sort:   sorts,
-				Cursor: cursorRaw,
-			}
-			c.Locals(pageInfoKey, pageInfo)
+		c.Locals(pageInfoKey, pageInfo)
+		if !pageInfo.ranged {
 			return c.Next()
 		}
 
-		page := max(fiber.Query(c, cfg.PageKey, cfg.DefaultPage), 1)
-		offset := max(fiber.Query(c, "offset", 0), 0)
-
-		c.Locals(pageInfoKey, NewPageInfo(page, limit, offset, sorts))
-		return c.Next()
+		if err := c.Next(); err != nil {
+			return err
+		}
+		writeContentRange(c, cfg.RangeUnit, pageInfo)
+		return nil
 	}
 }
 
@@ -78,9 +75,54 @@ func FromContext(c fiber.Ctx) (*PageInfo, bool) {
 	return nil, false
 }
 
+// fiberInput builds the Parser input for a Fiber request. The body is only
+// decoded when one of the sources reads it.
+func fiberInput(c fiber.Ctx, cfg Config) Input {
+	// ParseQuery keeps every pair it could decode, which is all the
+	// parser needs.
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	in := Input{
+		Context: c.Context(),
+		Query:   query,
+		Header: ValuesFunc(func(key string) string {
+			return c.Get(key)
+		}),
+	}
+	if cfg.MaxLimitFunc != nil {
+		in.MaxLimit = cfg.MaxLimitFunc(c)
+	}
+	if cfg.DefaultLimitFunc != nil {
+		in.DefaultLimit = cfg.DefaultLimitFunc(c)
+	}
+	if slices.Contains(cfg.Sources, SourceBody) && c.Is("json") {
+		in.Body = JSONBody(c.Body())
+	}
+	if slices.Contains(cfg.Sources, SourceForm) {
+		in.Form = formValues(c)
+	}
+	return in
+}
+
+// formValues reads the fields of a urlencoded or multipart form body. Both
+// come back empty unless the body has the matching content type.
+func formValues(c fiber.Ctx) url.Values {
+	values := make(url.Values)
+	for key, value := range c.RequestCtx().PostArgs().All() {
+		values.Add(string(key), string(value))
+	}
+
+	if form, err := c.MultipartForm(); err == nil {
+		for key, value := range form.Value {
+			values[key] = append(values[key], value...)
+		}
+	}
+	return values
+}
+
 func parseSortQuery(query string, allowedSorts []string, defaultSort string) []SortField {
 	if query == "" {
-		return []SortField{{Field: defaultSort, Order: ASC}}
+		return []SortField{defaultSortField(defaultSort)}
 	}
 
 	fields := strings.Split(query, ",")
@@ -98,8 +140,36 @@ func parseSortQuery(query string, allowedSorts []string, defaultSort string) []S
 	}
 
 	if len(sortFields) == 0 {
-		return []SortField{{Field: defaultSort, Order: ASC}}
+		return []SortField{defaultSortField(defaultSort)}
 	}
 
 	return sortFields
 }
+
+// defaultSortField reads DefaultSort, which like the sort query may be
+// prefixed with "-" for descending order.
+func defaultSortField(defaultSort string) SortField {
+	if field, ok := strings.CutPrefix(defaultSort, "-"); ok {
+		return SortField{Field: field, Order: DESC}
+	}
+	return SortField{Field: defaultSort, Order: ASC}
+}
+
+// withTiebreaker appends the tiebreaker to sorts unless it is already
+// sorted on. It takes the direction of the last sort field.
+func withTiebreaker(sorts []SortField, tiebreaker string) []SortField {
+	if tiebreaker == "" {
+		return sorts
+	}
+	for _, field := range sorts {
+		if field.Field == tiebreaker {
+			return sorts
+		}
+	}
+
+	order := ASC
+	if len(sorts) > 0 {
+		order = sorts[len(sorts)-1].Order
+	}
+	return append(sorts, SortField{Field: tiebreaker, Order: order})
+}
@@ -585,6 +585,13 @@ func TestParseSortQuery(t *testing.T) {
 			"id",
 			[]SortField{{Field: "id", Order: ASC}},
 		},
+		{
+			"Descending default",
+			"",
+			[]string{"id", "name", "date"},
+			"-date",
+			[]SortField{{Field: "date", Order: DESC}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -597,6 +604,45 @@ func TestParseSortQuery(t *testing.T) {
 	}
 }
 
+func TestWithTiebreaker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		sorts      []SortField
+		tiebreaker string
+		expected   []SortField
+	}{
+		{
+			"No tiebreaker",
+			[]SortField{{Field: "created_at", Order: DESC}},
+			"",
+			[]SortField{{Field: "created_at", Order: DESC}},
+		},
+		{
+			"Appended with last direction",
+			[]SortField{{Field: "name", Order: ASC}, {Field: "created_at", Order: DESC}},
+			"id",
+			[]SortField{{Field: "name", Order: ASC}, {Field: "created_at", Order: DESC}, {Field: "id", Order: DESC}},
+		},
+		{
+			"Already sorted on",
+			[]SortField{{Field: "id", Order: DESC}, {Field: "name", Order: ASC}},
+			"id",
+			[]SortField{{Field: "id", Order: DESC}, {Field: "name", Order: ASC}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if result := withTiebreaker(tt.sorts, tt.tiebreaker); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("withTiebreaker() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func BenchmarkPaginateMiddleware(b *testing.B) {
 	app := fiber.New()
 	app.Use(New())
@@ -974,3 +1020,126 @@ func BenchmarkPaginateCursorMiddleware(b *testing.B) {
 		}
 	}
 }
+
+func Test_PaginateAIP158(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(ConfigAIP158))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, ok := FromContext(c)
+		if !ok {
+			return fiber.ErrBadRequest
+		}
+		if pageInfo.Cursor == "" {
+			pageInfo.SetNextCursor(map[string]any{"id": float64(10)})
+		}
+		return c.JSON(fiber.Map{
+			"page_size":       pageInfo.Limit,
+			"next_page_token": pageInfo.NextPageToken(),
+		})
+	})
+
+	type aipResponse struct {
+		PageSize      int    `json:"page_size"`
+		NextPageToken string `json:"next_page_token"`
+	}
+
+	// page_size=0 means the server default
+	resp, err := app.Test(httptest.NewRequest("GET", "/?filter=active&page_size=0", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var first aipResponse
+	if err := json.NewDecoder(resp.Body).Decode(&first); err != nil {
+		t.Fatal(err)
+	}
+	if first.PageSize != 10 {
+		t.Errorf("page_size = %d, want 10", first.PageSize)
+	}
+	if first.NextPageToken == "" {
+		t.Fatal("next_page_token is empty")
+	}
+
+	testCases := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"Same parameters", "/?filter=active&page_token=" + first.NextPageToken, 200},
+		{"Changed page_size", "/?filter=active&page_size=5&page_token=" + first.NextPageToken, 200},
+		{"Changed filter", "/?filter=archived&page_token=" + first.NextPageToken, 400},
+		{"Unbound token", "/?filter=active&page_token=" + base64.RawURLEncoding.EncodeToString([]byte(`{"id":10}`)), 400},
+		{"Negative page_size", "/?filter=active&page_size=-5", 400},
+		{"Malformed page_size", "/?filter=active&page_size=ten", 400},
+		{"Page parameter", "/?filter=active&page=2", 400},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.status)
+			}
+		})
+	}
+}
+
+func Test_PaginateLimitFuncs(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		MaxLimitFunc: func(c fiber.Ctx) int {
+			if c.Get("X-Plan") == "paid" {
+				return 500
+			}
+			return 20
+		},
+		DefaultLimitFunc: func(c fiber.Ctx) int {
+			if c.Get("X-Plan") == "paid" {
+				return 100
+			}
+			return 0
+		},
+	}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		return c.JSON(pageInfo)
+	})
+
+	testCases := []struct {
+		name  string
+		url   string
+		plan  string
+		limit int
+	}{
+		{"Anonymous capped", "/?limit=100", "", 20},
+		{"Anonymous default", "/", "", 10},
+		{"Paid raised cap", "/?limit=450", "paid", 450},
+		{"Paid default", "/", "paid", 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.url, nil)
+			if tc.plan != "" {
+				req.Header.Set("X-Plan", tc.plan)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pageInfo PageInfo
+			if err := json.NewDecoder(resp.Body).Decode(&pageInfo); err != nil {
+				t.Fatal(err)
+			}
+			if pageInfo.Limit != tc.limit {
+				t.Errorf("Limit = %d, want %d", pageInfo.Limit, tc.limit)
+			}
+		})
+	}
+}
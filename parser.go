@@ -0,0 +1,295 @@
+package spindle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor is not valid base64 JSON.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrCursorMismatch is returned when Config.BindCursor is enabled and a
+	// cursor is sent with different parameters than the request that
+	// issued it.
+	ErrCursorMismatch = errors.New("cursor does not match request")
+
+	// ErrInvalidLimit is returned in strict mode when the limit is not a
+	// non-negative integer.
+	ErrInvalidLimit = errors.New("invalid limit")
+)
+
+// Values looks up a request value by key. url.Values and http.Header both
+// satisfy it.
+type Values interface {
+	Get(key string) string
+}
+
+// ValuesFunc adapts a lookup function to Values.
+type ValuesFunc func(key string) string
+
+// Get returns f(key).
+func (f ValuesFunc) Get(key string) string {
+	return f(key)
+}
+
+// Input is the framework-independent view of a request read by a Parser.
+// Only the parts named by Config.Sources and Config.RangeHeader are used,
+// so the rest may be left nil.
+type Input struct {
+	// Query holds the query string parameters.
+	Query url.Values
+
+	// Header holds the request headers.
+	Header Values
+
+	// Body holds the top-level fields of a JSON request body, rendered as
+	// they would appear in a query string. Use JSONBody so Config.BindCursor
+	// can bind cursors to the body.
+	Body Values
+
+	// Form holds urlencoded or multipart form fields. Config.BindCursor
+	// binds cursors to them when they are url.Values.
+	Form Values
+
+	// MaxLimit caps the limit of this request instead of the MaxLimit
+	// constant when positive.
+	MaxLimit int
+
+	// DefaultLimit replaces Config.DefaultLimit for this request when
+	// positive.
+	DefaultLimit int
+
+	// Context is the context of the request, passed to Config.Logger so
+	// warnings carry its trace and log attributes. Nil means
+	// context.Background.
+	Context context.Context
+}
+
+// Parser resolves pagination parameters into a PageInfo. It holds all the
+// parsing and validation behind New, so the same rules can be applied from
+// net/http, chi, gRPC gateways or any other framework.
+type Parser struct {
+	cfg Config
+}
+
+// NewParser creates a Parser with the given config.
+func NewParser(config ...Config) *Parser {
+	cfg := configDefault(config...)
+	if cfg.DefaultSort == "" {
+		cfg.DefaultSort = "id"
+	}
+	return &Parser{cfg: cfg}
+}
+
+// Parse resolves the pagination parameters of a request. Errors are meant
+// to be reported to the client as 400 Bad Request.
+func (p *Parser) Parse(in Input) (*PageInfo, error) {
+	cfg := p.cfg
+
+	maxLimit := MaxLimit
+	if in.MaxLimit > 0 {
+		maxLimit = in.MaxLimit
+	}
+	defaultLimit := cfg.DefaultLimit
+	if in.DefaultLimit > 0 {
+		defaultLimit = in.DefaultLimit
+	}
+	defaultLimit = min(defaultLimit, maxLimit)
+
+	var w warnings
+	// clamped is set when the request is served past a limit of this
+	// config rather than normalized, so it is not redirected permanently.
+	var clamped bool
+
+	limit := defaultLimit
+	if raw := in.get(cfg.Sources, cfg.LimitKey); raw != "" {
+		n, err := strconv.Atoi(raw)
+		switch {
+		case (err != nil || n < 0) && cfg.Strict:
+			return nil, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidLimit, cfg.LimitKey)
+		case err != nil || n < 0:
+			w.add(cfg.LimitKey, raw, strconv.Itoa(defaultLimit), cfg.LimitKey+" must be a positive integer")
+		case n > maxLimit:
+			limit = maxLimit
+			clamped = true
+			w.add(cfg.LimitKey, raw, strconv.Itoa(maxLimit), cfg.LimitKey+" exceeds the maximum of "+strconv.Itoa(maxLimit))
+		case n > 0:
+			limit = n
+		}
+	}
+
+	sortRaw := in.get(cfg.Sources, cfg.SortKey)
+	for _, field := range strings.Split(sortRaw, ",") {
+		if name := strings.TrimPrefix(field, "-"); name != "" && !slices.Contains(cfg.AllowedSorts, name) {
+			w.add(cfg.SortKey, field, "", "sort field "+strconv.Quote(name)+" is not allowed")
+		}
+	}
+	sorts := parseSortQuery(sortRaw, cfg.AllowedSorts, cfg.DefaultSort)
+	sorts, err := limitSorts(cfg, sorts, &w)
+	if err != nil {
+		return nil, err
+	}
+	requestedSorts := sorts
+	sorts = withTiebreaker(sorts, cfg.Tiebreaker)
+
+	var binding, boundQuery string
+	if cfg.BindCursor {
+		binding = cursorBinding(in, cfg)
+		boundQuery = bindingArgs(in.Query, cfg).Encode()
+	}
+
+	cursorRaw := in.get(cfg.Sources, cfg.CursorKey)
+	if cursorRaw == "" {
+		cursorRaw = in.get(cfg.Sources, cfg.CursorParam)
+	}
+
+	var rangeOffset, rangeLimit int
+	var ranged bool
+	if cfg.RangeHeader && in.Header != nil {
+		rangeOffset, rangeLimit, ranged = parseRangeHeader(in.Header.Get("Range"), cfg.RangeUnit)
+	}
+
+	mode, err := resolveMode(cfg.Mode, cfg.Strict, presence{
+		cursor: cursorRaw != "",
+		page:   in.get(cfg.Sources, cfg.PageKey) != "",
+		offset: in.get(cfg.Sources, cfg.OffsetKey) != "" || ranged,
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.ignored(in, cfg, mode, ranged)
+
+	var page, offset int
+	switch {
+	case mode == ModeCursor:
+		if cursorRaw != "" {
+			if err := checkCursor(cursorRaw, cfg.BindCursor, binding); err != nil {
+				return nil, err
+			}
+		}
+	case mode == ModePage:
+		page = in.getInt(cfg.Sources, cfg.PageKey, cfg.DefaultPage, 1, &w)
+	case ranged:
+		offset = rangeOffset
+		if rangeLimit > maxLimit {
+			w.add("Range", in.Header.Get("Range"), strconv.Itoa(maxLimit), "range exceeds the maximum of "+strconv.Itoa(maxLimit)+" items")
+		}
+		if rangeLimit > 0 {
+			limit = min(rangeLimit, maxLimit)
+		}
+		page = offset/limit + 1
+	default:
+		// The page key does not belong to offset mode and is not read.
+		page = cfg.DefaultPage
+		offset = in.getInt(cfg.Sources, cfg.OffsetKey, 0, 0, &w)
+	}
+
+	if mode != ModeCursor {
+		requested, requestedPage, requestedOffset := mode, page, offset
+		page, offset, mode, err = limitDepth(cfg, mode, page, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		w.depth(cfg, requested, mode, requestedPage, page, requestedOffset, offset)
+		clamped = clamped || mode != requested || page != requestedPage || offset != requestedOffset
+	}
+
+	var snapshot string
+	var carriedSnapshot bool
+	if cfg.Snapshot && mode != ModeCursor {
+		snapshot, carriedSnapshot, err = resolveSnapshot(in, cfg, page, offset, &w)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pageInfo := NewPageInfo(page, limit, offset, sorts)
+	pageInfo.Mode = mode
+	pageInfo.keys = queryKeys{
+		page:        cfg.PageKey,
+		offset:      cfg.OffsetKey,
+		limit:       cfg.LimitKey,
+		sort:        cfg.SortKey,
+		cursor:      cfg.CursorKey,
+		cursorParam: cfg.CursorParam,
+		snapshot:    cfg.SnapshotKey,
+	}
+	pageInfo.snapshotSecret = cfg.SnapshotSecret
+	if snapshot != "" {
+		pageInfo.SetSnapshot(snapshot)
+		pageInfo.carriedSnapshot = carriedSnapshot
+	}
+	pageInfo.binding = binding
+	pageInfo.boundQuery = boundQuery
+	pageInfo.ranged = ranged && mode == ModeOffset
+	if mode == ModeCursor {
+		pageInfo.Cursor = cursorRaw
+	}
+	pageInfo.Warnings = w
+	pageInfo.transparent = cfg.Transparent
+	if cfg.Canonical && !pageInfo.ranged && slices.Contains(cfg.Sources, SourceQuery) {
+		pageInfo.canonical = canonicalQuery(in.Query, cfg, pageInfo, requestedSorts, defaultLimit)
+		pageInfo.hasCanonical = true
+		pageInfo.clamped = clamped
+	}
+
+	w.log(in.Context, cfg.Logger)
+	return pageInfo, nil
+}
+
+// checkCursor validates a cursor token and, when bound, that it was issued
+// for a request with the same binding.
+func checkCursor(cursor string, bound bool, binding string) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return ErrInvalidCursor
+	}
+	if bound && obj[cursorBindingKey] != binding {
+		return ErrCursorMismatch
+	}
+	return nil
+}
+
+// cursorBinding hashes the parameters a cursor is bound to: the query
+// string, and the fields of the body and form when they are sources, so
+// the filters of a POST search are bound too. Headers are not bound, as
+// most of them describe the client rather than the request. The cursor and
+// limit keys are excluded so the page size may change freely.
+func cursorBinding(in Input, cfg Config) string {
+	data := bindingArgs(in.Query, cfg).Encode()
+	for _, source := range cfg.Sources {
+		if source != SourceBody && source != SourceForm {
+			continue
+		}
+		if args := bindingArgs(in.list(source), cfg); len(args) > 0 {
+			data += "\x00" + strconv.Itoa(int(source)) + "\x00" + args.Encode()
+		}
+	}
+
+	sum := sha256.Sum256([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// bindingArgs returns values without the cursor and limit keys.
+func bindingArgs(values url.Values, cfg Config) url.Values {
+	args := make(url.Values, len(values))
+	for key, value := range values {
+		if key != cfg.CursorKey && key != cfg.LimitKey && key != cfg.CursorParam {
+			args[key] = value
+		}
+	}
+	return args
+}
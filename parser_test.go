@@ -0,0 +1,260 @@
+package spindle
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParserParse(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{
+		SortKey:      "sort",
+		AllowedSorts: []string{"id", "name"},
+	})
+
+	pageInfo, err := parser.Parse(Input{
+		Query: url.Values{"page": {"3"}, "limit": {"25"}, "sort": {"-name"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pageInfo.Page != 3 || pageInfo.Limit != 25 || pageInfo.Start() != 50 {
+		t.Errorf("Page, Limit, Start() = %d, %d, %d, want 3, 25, 50", pageInfo.Page, pageInfo.Limit, pageInfo.Start())
+	}
+	if pageInfo.Mode != ModePage {
+		t.Errorf("Mode = %q, want %q", pageInfo.Mode, ModePage)
+	}
+	expectedSort := []SortField{{Field: "name", Order: DESC}}
+	if !reflect.DeepEqual(pageInfo.Sort, expectedSort) {
+		t.Errorf("Sort = %v, want %v", pageInfo.Sort, expectedSort)
+	}
+}
+
+func TestParserParseDefaults(t *testing.T) {
+	t.Parallel()
+
+	pageInfo, err := NewParser().Parse(Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pageInfo.Page != 1 || pageInfo.Limit != 10 {
+		t.Errorf("Page, Limit = %d, %d, want 1, 10", pageInfo.Page, pageInfo.Limit)
+	}
+	expectedSort := []SortField{{Field: "id", Order: ASC}}
+	if !reflect.DeepEqual(pageInfo.Sort, expectedSort) {
+		t.Errorf("Sort = %v, want %v", pageInfo.Sort, expectedSort)
+	}
+}
+
+func TestParserParseErrors(t *testing.T) {
+	t.Parallel()
+
+	unbound := base64.RawURLEncoding.EncodeToString([]byte(`{"id":1}`))
+
+	tests := []struct {
+		name  string
+		cfg   Config
+		query url.Values
+		err   error
+	}{
+		{"Invalid base64", Config{}, url.Values{"cursor": {"not-valid!!!"}}, ErrInvalidCursor},
+		{"Invalid JSON", Config{}, url.Values{"cursor": {base64.RawURLEncoding.EncodeToString([]byte("x"))}}, ErrInvalidCursor},
+		{"Unbound cursor", Config{BindCursor: true}, url.Values{"cursor": {unbound}}, ErrCursorMismatch},
+		{"Conflict", Config{Strict: true}, url.Values{"page": {"2"}, "offset": {"5"}}, ErrConflictingParameters},
+		{"Strict negative limit", Config{Strict: true}, url.Values{"limit": {"-5"}}, ErrInvalidLimit},
+		{"Strict malformed limit", Config{Strict: true}, url.Values{"limit": {"ten"}}, ErrInvalidLimit},
+		{"Too deep", Config{MaxPage: 5, DepthPolicy: DepthReject}, url.Values{"page": {"6"}}, ErrDepthExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pageInfo, err := NewParser(tt.cfg).Parse(Input{Query: tt.query})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.err)
+			}
+			if pageInfo != nil {
+				t.Errorf("Parse() = %+v, want nil on error", pageInfo)
+			}
+		})
+	}
+}
+
+func TestParserParseSources(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{
+		Sources:     []Source{SourceHeader, SourceBody, SourceForm, SourceQuery},
+		RangeHeader: true,
+	})
+
+	pageInfo, err := parser.Parse(Input{
+		Query:  url.Values{"page": {"9"}, "limit": {"15"}},
+		Header: http.Header{"Page": {"4"}},
+		Body:   JSONBody([]byte(`{"limit": 30}`)),
+		Form:   url.Values{"limit": {"40"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pageInfo.Page != 4 {
+		t.Errorf("Page = %d, want 4 from header", pageInfo.Page)
+	}
+	if pageInfo.Limit != 30 {
+		t.Errorf("Limit = %d, want 30 from body", pageInfo.Limit)
+	}
+}
+
+func TestParserParseRangeHeader(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{RangeHeader: true})
+
+	pageInfo, err := parser.Parse(Input{Header: http.Header{"Range": {"items=20-39"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pageInfo.Mode != ModeOffset || pageInfo.Offset != 20 || pageInfo.Limit != 20 {
+		t.Errorf("Mode, Offset, Limit = %q, %d, %d, want offset, 20, 20", pageInfo.Mode, pageInfo.Offset, pageInfo.Limit)
+	}
+	if !pageInfo.ranged {
+		t.Error("ranged = false, want true")
+	}
+}
+
+func TestJSONBody(t *testing.T) {
+	t.Parallel()
+
+	values := JSONBody([]byte(`{"page": 2, "sort": ["a", "-b"]}`))
+	if got := values.Get("page"); got != "2" {
+		t.Errorf("Get(page) = %q, want %q", got, "2")
+	}
+	if got := values.Get("sort"); got != "a,-b" {
+		t.Errorf("Get(sort) = %q, want %q", got, "a,-b")
+	}
+
+	if got := JSONBody([]byte(`[1, 2]`)).Get("page"); got != "" {
+		t.Errorf("Get(page) = %q, want empty for a non-object body", got)
+	}
+}
+
+func TestParserParseTiebreaker(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{
+		SortKey:      "sort",
+		AllowedSorts: []string{"created_at"},
+		DefaultSort:  "-created_at",
+		Tiebreaker:   "id",
+	})
+
+	for _, query := range []url.Values{{"sort": {"-created_at"}}, {}} {
+		pageInfo, err := parser.Parse(Input{Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []SortField{{Field: "created_at", Order: DESC}, {Field: "id", Order: DESC}}
+		if !reflect.DeepEqual(pageInfo.Sort, expected) {
+			t.Errorf("Sort = %v, want %v", pageInfo.Sort, expected)
+		}
+	}
+}
+
+func TestParserParseInputLimits(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{DefaultLimit: 10})
+
+	tests := []struct {
+		name     string
+		in       Input
+		expected int
+	}{
+		{"Raised cap", Input{Query: url.Values{"limit": {"400"}}, MaxLimit: 500}, 400},
+		{"Raised cap clamps", Input{Query: url.Values{"limit": {"900"}}, MaxLimit: 500}, 500},
+		{"Lowered cap", Input{Query: url.Values{"limit": {"50"}}, MaxLimit: 20}, 20},
+		{"Default override", Input{DefaultLimit: 25}, 25},
+		{"Default capped", Input{DefaultLimit: 50, MaxLimit: 20}, 20},
+		{"Config default capped", Input{MaxLimit: 5}, 5},
+		{"Unset", Input{Query: url.Values{"limit": {"900"}}}, MaxLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pageInfo, err := parser.Parse(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pageInfo.Limit != tt.expected {
+				t.Errorf("Limit = %d, want %d", pageInfo.Limit, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParserParseBindCursorURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := ConfigAIP158
+	cfg.SortKey = "sort"
+	cfg.AllowedSorts = []string{"name"}
+	parser := NewParser(cfg)
+
+	first, err := parser.Parse(Input{Query: url.Values{"sort": {"name"}, "filter": {"active"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.SetNextCursor(map[string]any{"name": "b"})
+
+	next, err := url.Parse(first.NextCursorURL("/books"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pageInfo, err := parser.Parse(Input{Query: next.Query()})
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v, want nil", next, err)
+	}
+	if pageInfo.Cursor != first.NextCursor {
+		t.Errorf("Cursor = %q, want %q", pageInfo.Cursor, first.NextCursor)
+	}
+}
+
+func TestParserParseBindCursorBody(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{Sources: []Source{SourceBody, SourceForm, SourceQuery}, BindCursor: true})
+
+	first, err := parser.Parse(Input{Body: JSONBody([]byte(`{"status": "active", "limit": 5}`))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.SetNextCursor(map[string]any{"id": float64(5)})
+
+	tests := []struct {
+		name string
+		in   Input
+		err  error
+	}{
+		{"Same body", Input{Body: JSONBody([]byte(`{"status": "active", "limit": 20, "cursor": "` + first.NextCursor + `"}`))}, nil},
+		{"Changed body", Input{Body: JSONBody([]byte(`{"status": "archived", "cursor": "` + first.NextCursor + `"}`))}, ErrCursorMismatch},
+		{"Changed form", Input{
+			Body: JSONBody([]byte(`{"status": "active", "cursor": "` + first.NextCursor + `"}`)),
+			Form: url.Values{"status": {"archived"}},
+		}, ErrCursorMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := parser.Parse(tt.in); !errors.Is(err, tt.err) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
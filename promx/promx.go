@@ -0,0 +1,111 @@
+// Package promx records spindle pagination usage as Prometheus metrics.
+package promx
+
+import (
+	"context"
+
+	"github.com/mutantkeyboard/spindle"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Observer is a spindle.Observer and prometheus.Collector recording:
+//
+//	spindle_pagination_requests_total{mode}         resolved requests
+//	spindle_pagination_limit{mode}                  histogram of Limit
+//	spindle_pagination_page                         histogram of Page in page mode
+//	spindle_pagination_offset                       histogram of Offset in offset mode
+//	spindle_pagination_sort_fields_total{field,order} sort fields used
+//	spindle_pagination_rejected_total{reason}       requests rejected with 400
+//
+// The invalid cursor rate is rejected_total{reason="invalid_cursor"} over
+// the sum of requests_total and rejected_total.
+type Observer struct {
+	requests *prometheus.CounterVec
+	limit    *prometheus.HistogramVec
+	page     prometheus.Histogram
+	offset   prometheus.Histogram
+	sorts    *prometheus.CounterVec
+	rejected *prometheus.CounterVec
+}
+
+var _ spindle.Observer = (*Observer)(nil)
+
+// New creates an Observer. Register it before use:
+//
+//	observer := promx.New()
+//	prometheus.MustRegister(observer)
+//	app.Use(spindle.New(spindle.Config{Observer: observer}))
+func New() *Observer {
+	return &Observer{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "spindle_pagination_requests_total",
+			Help: "Paginated requests by resolved mode.",
+		}, []string{"mode"}),
+		limit: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "spindle_pagination_limit",
+			Help:    "Resolved page size.",
+			Buckets: []float64{1, 5, 10, 20, 25, 50, 100, 250, 500, 1000},
+		}, []string{"mode"}),
+		page: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "spindle_pagination_page",
+			Help:    "Requested page number in page mode.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}),
+		offset: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "spindle_pagination_offset",
+			Help:    "Requested offset in offset mode.",
+			Buckets: prometheus.ExponentialBuckets(10, 4, 9),
+		}),
+		sorts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "spindle_pagination_sort_fields_total",
+			Help: "Sort fields used, by field and order.",
+		}, []string{"field", "order"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "spindle_pagination_rejected_total",
+			Help: "Requests rejected with 400, by reason.",
+		}, []string{"reason"}),
+	}
+}
+
+// Observe records a resolved PageInfo.
+func (o *Observer) Observe(_ context.Context, p *spindle.PageInfo) {
+	mode := string(p.Mode)
+	o.requests.WithLabelValues(mode).Inc()
+	o.limit.WithLabelValues(mode).Observe(float64(p.Limit))
+
+	switch p.Mode {
+	case spindle.ModePage:
+		o.page.Observe(float64(p.Page))
+	case spindle.ModeOffset:
+		o.offset.Observe(float64(p.Start()))
+	}
+
+	for _, field := range p.Sort {
+		o.sorts.WithLabelValues(field.Field, string(field.Order)).Inc()
+	}
+}
+
+// Reject records a rejected request.
+func (o *Observer) Reject(_ context.Context, err error) {
+	o.rejected.WithLabelValues(spindle.RejectReason(err)).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (o *Observer) Describe(ch chan<- *prometheus.Desc) {
+	o.requests.Describe(ch)
+	o.limit.Describe(ch)
+	o.page.Describe(ch)
+	o.offset.Describe(ch)
+	o.sorts.Describe(ch)
+	o.rejected.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (o *Observer) Collect(ch chan<- prometheus.Metric) {
+	o.requests.Collect(ch)
+	o.limit.Collect(ch)
+	o.page.Collect(ch)
+	o.offset.Collect(ch)
+	o.sorts.Collect(ch)
+	o.rejected.Collect(ch)
+}
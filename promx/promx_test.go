@@ -0,0 +1,68 @@
+package promx
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/mutantkeyboard/spindle"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	observer := New()
+	if err := prometheus.NewPedanticRegistry().Register(observer); err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Use(spindle.New(spindle.Config{
+		SortKey:      "sort",
+		AllowedSorts: []string{"id", "name"},
+		Observer:     observer,
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	invalid := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+	for _, url := range []string{
+		"/?page=3&limit=20&sort=name",
+		"/?page=1&sort=-name,id",
+		"/?offset=40",
+		"/?cursor=" + invalid,
+	} {
+		if _, err := app.Test(httptest.NewRequest("GET", url, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counters := []struct {
+		name     string
+		counter  prometheus.Counter
+		expected float64
+	}{
+		{"page requests", observer.requests.WithLabelValues("page"), 2},
+		{"offset requests", observer.requests.WithLabelValues("offset"), 1},
+		{"name asc", observer.sorts.WithLabelValues("name", "asc"), 1},
+		{"name desc", observer.sorts.WithLabelValues("name", "desc"), 1},
+		{"id asc", observer.sorts.WithLabelValues("id", "asc"), 2},
+		{"invalid cursors", observer.rejected.WithLabelValues("invalid_cursor"), 1},
+	}
+	for _, c := range counters {
+		if got := testutil.ToFloat64(c.counter); got != c.expected {
+			t.Errorf("%s = %v, want %v", c.name, got, c.expected)
+		}
+	}
+
+	if count := testutil.CollectAndCount(observer, "spindle_pagination_limit"); count != 2 {
+		t.Errorf("limit series = %d, want 2", count)
+	}
+	if count := testutil.CollectAndCount(observer, "spindle_pagination_page", "spindle_pagination_offset"); count != 2 {
+		t.Errorf("page and offset series = %d, want 2", count)
+	}
+}
@@ -0,0 +1,79 @@
+package spindle
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// parseRangeHeader parses a single "unit=first-last" range such as
+// "items=0-24". The last position may be omitted, in which case limit is 0.
+// Anything else reports false and the header is ignored, as RFC 9110 allows.
+func parseRangeHeader(header, unit string) (offset, limit int, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), unit+"=")
+	if !found {
+		return 0, 0, false
+	}
+
+	first, last, found := strings.Cut(spec, "-")
+	if !found || strings.Contains(last, ",") {
+		return 0, 0, false
+	}
+
+	offset, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || offset < 0 {
+		return 0, 0, false
+	}
+
+	last = strings.TrimSpace(last)
+	if last == "" {
+		return offset, 0, true
+	}
+
+	end, err := strconv.Atoi(last)
+	if err != nil || end < offset {
+		return 0, 0, false
+	}
+
+	return offset, end - offset + 1, true
+}
+
+// contentRange returns the Content-Range header value and status answering
+// a Range request. ok is false until the handler has recorded a total.
+func contentRange(unit string, p *PageInfo) (value string, status int, ok bool) {
+	if !p.hasTotal && p.Total == 0 {
+		return "", 0, false
+	}
+
+	total := strconv.Itoa(p.Total)
+	start := p.Start()
+	switch {
+	case p.Total == 0:
+		return unit + " */0", http.StatusOK, true
+	case start >= p.Total:
+		return unit + " */" + total, http.StatusRequestedRangeNotSatisfiable, true
+	}
+
+	end := min(start+p.Limit, p.Total) - 1
+	return unit + " " + strconv.Itoa(start) + "-" + strconv.Itoa(end) + "/" + total, http.StatusPartialContent, true
+}
+
+// writeContentRange sets the Content-Range header and status answering a
+// Range request on a Fiber response.
+func writeContentRange(c fiber.Ctx, unit string, p *PageInfo) {
+	value, status, ok := contentRange(unit, p)
+	if !ok {
+		return
+	}
+
+	c.Set(fiber.HeaderContentRange, value)
+	switch {
+	case status == fiber.StatusRequestedRangeNotSatisfiable:
+		c.Response().ResetBody()
+		c.Status(status)
+	case c.Response().StatusCode() == fiber.StatusOK:
+		c.Status(status)
+	}
+}
@@ -0,0 +1,161 @@
+package spindle
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestParseRangeHeader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		offset int
+		limit  int
+		ok     bool
+	}{
+		{"First page", "items=0-24", 0, 25, true},
+		{"Later page", "items=50-74", 50, 25, true},
+		{"Single item", "items=3-3", 3, 1, true},
+		{"Open ended", "items=20-", 20, 0, true},
+		{"Whitespace", " items=0 - 9 ", 0, 10, true},
+		{"Empty", "", 0, 0, false},
+		{"Wrong unit", "bytes=0-24", 0, 0, false},
+		{"Multiple ranges", "items=0-9,20-29", 0, 0, false},
+		{"Suffix range", "items=-10", 0, 0, false},
+		{"Reversed", "items=10-5", 0, 0, false},
+		{"Not a number", "items=a-b", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, limit, ok := parseRangeHeader(tt.header, "items")
+			if ok != tt.ok || offset != tt.offset || limit != tt.limit {
+				t.Errorf("parseRangeHeader(%q) = (%d, %d, %v), want (%d, %d, %v)",
+					tt.header, offset, limit, ok, tt.offset, tt.limit, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_PaginateRangeHeader(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{RangeHeader: true}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, ok := FromContext(c)
+		if !ok {
+			return fiber.ErrBadRequest
+		}
+		pageInfo.SetTotal(319)
+		return c.JSON(pageInfo)
+	})
+
+	testCases := []struct {
+		name         string
+		rangeHeader  string
+		status       int
+		contentRange string
+	}{
+		{"First page", "items=0-24", fiber.StatusPartialContent, "items 0-24/319"},
+		{"Last partial page", "items=300-324", fiber.StatusPartialContent, "items 300-318/319"},
+		{"Limit capped", "items=0-499", fiber.StatusPartialContent, "items 0-99/319"},
+		{"Past the end", "items=400-424", fiber.StatusRequestedRangeNotSatisfiable, "items */319"},
+		{"No Range header", "", fiber.StatusOK, ""},
+		{"Malformed header ignored", "items=abc", fiber.StatusOK, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.rangeHeader != "" {
+				req.Header.Set(fiber.HeaderRange, tc.rangeHeader)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.status)
+			}
+			if got := resp.Header.Get(fiber.HeaderContentRange); got != tc.contentRange {
+				t.Errorf("Content-Range = %q, want %q", got, tc.contentRange)
+			}
+			if got := resp.Header.Get(fiber.HeaderAcceptRanges); got != "items" {
+				t.Errorf("Accept-Ranges = %q, want %q", got, "items")
+			}
+		})
+	}
+}
+
+func Test_PaginateRangeHeaderPageInfo(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{RangeHeader: true, RangeUnit: "rows"}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, ok := FromContext(c)
+		if !ok {
+			return fiber.ErrBadRequest
+		}
+		return c.JSON(Response{
+			Page:   pageInfo.Page,
+			Limit:  pageInfo.Limit,
+			Offset: pageInfo.Offset,
+			Start:  pageInfo.Start(),
+		})
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(fiber.HeaderRange, "rows=40-59")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a total the handler's status is left alone
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentRange); got != "" {
+		t.Errorf("Content-Range = %q, want empty without a total", got)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"page":3,"limit":20,"offset":40,"start":40,"sort":null,"next_PageURL":"","prev_PageURL":""}`
+	if string(body) != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
+
+func Test_PaginateRangeHeaderEmptyCollection(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{RangeHeader: true}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		pageInfo.SetTotal(0)
+		return c.JSON([]any{})
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(fiber.HeaderRange, "items=0-24")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentRange); got != "items */0" {
+		t.Errorf("Content-Range = %q, want %q", got, "items */0")
+	}
+}
@@ -0,0 +1,182 @@
+package spindle
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SortRegistry holds the sortable fields of a model type T, declared with
+// struct tags:
+//
+//	type User struct {
+//		ID        int       `json:"id" spindle:"sort"`
+//		Name      string    `json:"name" spindle:"sort,column=display_name"`
+//		CreatedAt time.Time `json:"created_at" spindle:"sort,default=desc"`
+//	}
+//
+// A field is exposed under its json name, or its Go name without one;
+// name= overrides it. column= sets the column it sorts by, defaulting to
+// the exposed name; sqlpage needs a result column of its query rather than
+// a table-qualified one. default=asc or default=desc marks the default sort.
+type SortRegistry[T any] struct {
+	names   []string
+	columns map[string]string
+	index   map[string][]int
+
+	defaultSort string
+}
+
+// NewSortRegistry builds the SortRegistry of T, which must be a struct type.
+func NewSortRegistry[T any]() (*SortRegistry[T], error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("spindle: sort registry needs a struct type, got %s", typ)
+	}
+
+	r := &SortRegistry[T]{
+		columns: make(map[string]string),
+		index:   make(map[string][]int),
+	}
+
+	for _, field := range reflect.VisibleFields(typ) {
+		tag, ok := field.Tag.Lookup("spindle")
+		if !ok || field.Anonymous || !field.IsExported() {
+			continue
+		}
+
+		name, column, order, err := parseSortTag(field, tag)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := r.columns[name]; exists {
+			return nil, fmt.Errorf("spindle: duplicate sort field %q on %s", name, typ)
+		}
+
+		r.names = append(r.names, name)
+		r.columns[name] = column
+		r.index[name] = field.Index
+
+		if order != "" {
+			if r.defaultSort != "" {
+				return nil, fmt.Errorf("spindle: more than one default sort on %s", typ)
+			}
+			r.defaultSort = name
+			if order == DESC {
+				r.defaultSort = "-" + name
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// MustSortRegistry is like NewSortRegistry but panics on error. It is meant
+// for package-level variables.
+func MustSortRegistry[T any]() *SortRegistry[T] {
+	r, err := NewSortRegistry[T]()
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// parseSortTag reads a spindle struct tag. order is empty unless the field
+// is the default sort.
+func parseSortTag(field reflect.StructField, tag string) (name, column string, order SortOrder, err error) {
+	parts := strings.Split(tag, ",")
+	if parts[0] != "sort" {
+		return "", "", "", fmt.Errorf("spindle: field %s: tag must start with \"sort\", got %q", field.Name, tag)
+	}
+
+	name = field.Name
+	if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName != "" && jsonName != "-" {
+		name = jsonName
+	}
+
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "name":
+			name = value
+		case "column":
+			column = value
+		case "default":
+			if value != string(ASC) && value != string(DESC) {
+				return "", "", "", fmt.Errorf("spindle: field %s: default must be asc or desc, got %q", field.Name, value)
+			}
+			order = SortOrder(value)
+		default:
+			return "", "", "", fmt.Errorf("spindle: field %s: unknown sort option %q", field.Name, option)
+		}
+	}
+
+	if name == "" {
+		return "", "", "", fmt.Errorf("spindle: field %s: empty sort name", field.Name)
+	}
+	if column == "" {
+		column = name
+	}
+	return name, column, order, nil
+}
+
+// AllowedSorts returns the sortable field names in declaration order.
+func (r *SortRegistry[T]) AllowedSorts() []string {
+	return append([]string(nil), r.names...)
+}
+
+// DefaultSort returns the default sort, prefixed with "-" when descending,
+// or an empty string if no field is marked as default.
+func (r *SortRegistry[T]) DefaultSort() string {
+	return r.defaultSort
+}
+
+// Column returns the column a sort field sorts by.
+func (r *SortRegistry[T]) Column(field string) (string, bool) {
+	column, ok := r.columns[field]
+	return column, ok
+}
+
+// Columns maps sort fields to their columns, for building queries. Fields
+// unknown to the registry are dropped.
+func (r *SortRegistry[T]) Columns(sort []SortField) []SortField {
+	columns := make([]SortField, 0, len(sort))
+	for _, field := range sort {
+		if column, ok := r.columns[field.Field]; ok {
+			columns = append(columns, SortField{Field: column, Order: field.Order})
+		}
+	}
+	return columns
+}
+
+// CursorValues returns the values of item for each sort field, keyed by
+// column, ready for PageInfo.SetNextCursor.
+func (r *SortRegistry[T]) CursorValues(item T, sort []SortField) map[string]any {
+	v := reflect.ValueOf(item)
+	values := make(map[string]any, len(sort))
+	for _, field := range sort {
+		index, ok := r.index[field.Field]
+		if !ok {
+			continue
+		}
+		fv, err := v.FieldByIndexErr(index)
+		if err != nil {
+			continue
+		}
+		values[r.columns[field.Field]] = fv.Interface()
+	}
+	return values
+}
+
+// Apply returns cfg with AllowedSorts taken from the registry. DefaultSort
+// and SortKey are filled in when unset.
+func (r *SortRegistry[T]) Apply(cfg Config) Config {
+	cfg.AllowedSorts = r.AllowedSorts()
+	if cfg.DefaultSort == "" {
+		cfg.DefaultSort = r.defaultSort
+	}
+	if cfg.SortKey == "" {
+		cfg.SortKey = "sort"
+	}
+	return cfg
+}
@@ -0,0 +1,180 @@
+package spindle
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type auditFields struct {
+	CreatedAt time.Time `json:"created_at" spindle:"sort,column=users.created_at,default=desc"`
+}
+
+type registryUser struct {
+	ID    int    `json:"id" spindle:"sort"`
+	Name  string `json:"name,omitempty" spindle:"sort,column=display_name"`
+	Email string `json:"email"`
+	Score int    `spindle:"sort,name=rank"`
+	Age   int    `spindle:"sort"`
+	auditFields
+}
+
+func TestNewSortRegistry(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewSortRegistry[registryUser]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"id", "name", "rank", "Age", "created_at"}
+	if allowed := r.AllowedSorts(); !reflect.DeepEqual(allowed, expected) {
+		t.Errorf("AllowedSorts() = %v, want %v", allowed, expected)
+	}
+	if r.DefaultSort() != "-created_at" {
+		t.Errorf("DefaultSort() = %q, want %q", r.DefaultSort(), "-created_at")
+	}
+
+	columns := map[string]string{
+		"id":         "id",
+		"name":       "display_name",
+		"rank":       "rank",
+		"created_at": "users.created_at",
+	}
+	for field, want := range columns {
+		if column, ok := r.Column(field); !ok || column != want {
+			t.Errorf("Column(%q) = %q, %v, want %q, true", field, column, ok, want)
+		}
+	}
+	if _, ok := r.Column("email"); ok {
+		t.Error("Column(email) ok = true, want false for an untagged field")
+	}
+}
+
+func TestNewSortRegistryErrors(t *testing.T) {
+	t.Parallel()
+
+	type badTag struct {
+		ID int `spindle:"filter"`
+	}
+	type badOption struct {
+		ID int `spindle:"sort,size=10"`
+	}
+	type badDefault struct {
+		ID int `spindle:"sort,default=up"`
+	}
+	type twoDefaults struct {
+		ID   int `spindle:"sort,default=asc"`
+		Name int `spindle:"sort,default=desc"`
+	}
+	type duplicate struct {
+		ID  int `json:"id" spindle:"sort"`
+		Key int `spindle:"sort,name=id"`
+	}
+
+	tests := []struct {
+		name  string
+		build func() error
+		want  string
+	}{
+		{"Not a struct", func() error { _, err := NewSortRegistry[int](); return err }, "needs a struct type"},
+		{"Bad tag", func() error { _, err := NewSortRegistry[badTag](); return err }, "must start with"},
+		{"Bad option", func() error { _, err := NewSortRegistry[badOption](); return err }, "unknown sort option"},
+		{"Bad default", func() error { _, err := NewSortRegistry[badDefault](); return err }, "asc or desc"},
+		{"Two defaults", func() error { _, err := NewSortRegistry[twoDefaults](); return err }, "more than one default"},
+		{"Duplicate", func() error { _, err := NewSortRegistry[duplicate](); return err }, "duplicate sort field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustSortRegistryPanics(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustSortRegistry did not panic for a non-struct type")
+		}
+	}()
+	MustSortRegistry[string]()
+}
+
+func TestSortRegistryColumns(t *testing.T) {
+	t.Parallel()
+
+	r := MustSortRegistry[registryUser]()
+	sort := []SortField{{Field: "name", Order: ASC}, {Field: "email", Order: ASC}, {Field: "created_at", Order: DESC}}
+
+	expected := []SortField{{Field: "display_name", Order: ASC}, {Field: "users.created_at", Order: DESC}}
+	if columns := r.Columns(sort); !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Columns() = %v, want %v", columns, expected)
+	}
+}
+
+func TestSortRegistryCursorValues(t *testing.T) {
+	t.Parallel()
+
+	r := MustSortRegistry[registryUser]()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := registryUser{ID: 7, Name: "ada", auditFields: auditFields{CreatedAt: created}}
+
+	values := r.CursorValues(user, []SortField{{Field: "created_at", Order: DESC}, {Field: "id", Order: DESC}})
+	expected := map[string]any{"users.created_at": created, "id": 7}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("CursorValues() = %v, want %v", values, expected)
+	}
+}
+
+func Test_PaginateSortRegistryApply(t *testing.T) {
+	t.Parallel()
+
+	r := MustSortRegistry[registryUser]()
+
+	app := fiber.New()
+	app.Use(New(r.Apply(Config{})))
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, ok := FromContext(c)
+		if !ok {
+			return fiber.ErrBadRequest
+		}
+		return c.JSON(pageInfo)
+	})
+
+	testCases := []struct {
+		query    string
+		expected []SortField
+	}{
+		{"/", []SortField{{Field: "created_at", Order: DESC}}},
+		{"/?sort=rank,-id", []SortField{{Field: "rank", Order: ASC}, {Field: "id", Order: DESC}}},
+		{"/?sort=email", []SortField{{Field: "created_at", Order: DESC}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result PageInfo
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Sort, tc.expected) {
+				t.Errorf("Sort = %v, want %v", result.Sort, tc.expected)
+			}
+		})
+	}
+}
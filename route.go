@@ -0,0 +1,211 @@
+package spindle
+
+import (
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// routeTable resolves Config.Routes against the routes of the app. Routes
+// are usually registered after the middleware is created, so the table is
+// built on the first request and rebuilt whenever routes are added.
+type routeTable struct {
+	parsers map[string]*Parser
+
+	snapshot atomic.Pointer[routeSnapshot]
+}
+
+// routeSnapshot holds, per method, the routes of the app in the order Fiber
+// tries them, up to the last one with an override.
+type routeSnapshot struct {
+	handlers uint32
+	routes   map[string][]routeEntry
+}
+
+// routeEntry is a route pattern and the Parser of its override, nil for
+// routes without one.
+type routeEntry struct {
+	pattern string
+	parser  *Parser
+}
+
+func newRouteTable(cfg Config) *routeTable {
+	if len(cfg.Routes) == 0 {
+		return nil
+	}
+
+	parsers := make(map[string]*Parser, len(cfg.Routes))
+	for key, override := range cfg.Routes {
+		parsers[key] = NewParser(mergeConfig(cfg, override))
+	}
+	return &routeTable{parsers: parsers}
+}
+
+// lookup returns the Parser of the route Fiber serves c with, or nil when
+// that route has no override. Routes are tried in registration order with
+// fiber.RoutePatternMatch, as Fiber does, so an earlier route such as
+// /users/new shadows a later /users/:id for the paths they both match.
+func (t *routeTable) lookup(c fiber.Ctx) *Parser {
+	if t == nil {
+		return nil
+	}
+
+	app := c.App()
+	snapshot := t.snapshot.Load()
+	if snapshot == nil || snapshot.handlers != app.HandlersCount() {
+		snapshot = t.build(app)
+		t.snapshot.Store(snapshot)
+	}
+
+	// Fiber strips trailing slashes from the path before it matches routes
+	// unless StrictRouting is set; RoutePatternMatch only does so for the
+	// pattern.
+	cfg := app.Config()
+	path := c.Path()
+	if !cfg.StrictRouting && len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	for _, route := range snapshot.routes[c.Method()] {
+		if fiber.RoutePatternMatch(path, route.pattern, cfg) {
+			return route.parser
+		}
+	}
+	return nil
+}
+
+// build orders the routes of app by method. Middleware routes are left
+// out, as they pass requests on to the route that serves them.
+func (t *routeTable) build(app *fiber.App) *routeSnapshot {
+	snapshot := &routeSnapshot{handlers: app.HandlersCount(), routes: make(map[string][]routeEntry)}
+	last := make(map[string]int)
+
+	for _, route := range app.GetRoutes(true) {
+		parser, ok := t.parsers[route.Name]
+		if !ok || route.Name == "" {
+			parser = t.parsers[route.Path]
+		}
+
+		routes := append(snapshot.routes[route.Method], routeEntry{pattern: route.Path, parser: parser})
+		snapshot.routes[route.Method] = routes
+		if parser != nil {
+			last[route.Method] = len(routes)
+		}
+	}
+
+	for method, routes := range snapshot.routes {
+		snapshot.routes[method] = routes[:last[method]]
+	}
+	return snapshot
+}
+
+// RouteConfig overrides the fields of Config for one route. A nil field
+// is inherited from the base config; any other value replaces it, so
+// Ptr(false) turns Strict off and Ptr(0) removes MaxPage. An empty,
+// non-nil slice clears AllowedSorts. Functions, the Observer and the
+// Logger can be replaced but not removed.
+type RouteConfig struct {
+	Next             func(c fiber.Ctx) bool
+	PageKey          *string
+	DefaultPage      *int
+	OffsetKey        *string
+	LimitKey         *string
+	DefaultLimit     *int
+	MaxLimitFunc     func(c fiber.Ctx) int
+	DefaultLimitFunc func(c fiber.Ctx) int
+	SortKey          *string
+	DefaultSort      *string
+	AllowedSorts     []string
+	Tiebreaker       *string
+	MaxSortFields    *int
+	SortConflict     *SortConflictPolicy
+	CursorKey        *string
+	CursorParam      *string
+	Snapshot         *bool
+	SnapshotKey      *string
+	SnapshotSecret   []byte
+	MaxPage          *int
+	MaxOffset        *int
+	DepthPolicy      *DepthPolicy
+	Mode             *Mode
+	Strict           *bool
+	BindCursor       *bool
+	RangeHeader      *bool
+	RangeUnit        *string
+	Sources          []Source
+	Logger           *slog.Logger
+	Transparent      *bool
+	Canonical        *bool
+	Observer         Observer
+}
+
+// Ptr returns a pointer to v, for the fields of RouteConfig.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// mergeConfig returns base with every field set in override replacing
+// its counterpart. Routes is not inherited.
+func mergeConfig(base Config, override RouteConfig) Config {
+	merged := base
+	merged.Routes = nil
+
+	if override.Next != nil {
+		merged.Next = override.Next
+	}
+	if override.MaxLimitFunc != nil {
+		merged.MaxLimitFunc = override.MaxLimitFunc
+	}
+	if override.DefaultLimitFunc != nil {
+		merged.DefaultLimitFunc = override.DefaultLimitFunc
+	}
+	if override.AllowedSorts != nil {
+		merged.AllowedSorts = override.AllowedSorts
+	}
+	if override.SnapshotSecret != nil {
+		merged.SnapshotSecret = override.SnapshotSecret
+	}
+	if override.Sources != nil {
+		merged.Sources = override.Sources
+	}
+	if override.Logger != nil {
+		merged.Logger = override.Logger
+	}
+	if override.Observer != nil {
+		merged.Observer = override.Observer
+	}
+
+	replace(&merged.PageKey, override.PageKey)
+	replace(&merged.DefaultPage, override.DefaultPage)
+	replace(&merged.OffsetKey, override.OffsetKey)
+	replace(&merged.LimitKey, override.LimitKey)
+	replace(&merged.DefaultLimit, override.DefaultLimit)
+	replace(&merged.SortKey, override.SortKey)
+	replace(&merged.DefaultSort, override.DefaultSort)
+	replace(&merged.Tiebreaker, override.Tiebreaker)
+	replace(&merged.MaxSortFields, override.MaxSortFields)
+	replace(&merged.SortConflict, override.SortConflict)
+	replace(&merged.CursorKey, override.CursorKey)
+	replace(&merged.CursorParam, override.CursorParam)
+	replace(&merged.Snapshot, override.Snapshot)
+	replace(&merged.SnapshotKey, override.SnapshotKey)
+	replace(&merged.MaxPage, override.MaxPage)
+	replace(&merged.MaxOffset, override.MaxOffset)
+	replace(&merged.DepthPolicy, override.DepthPolicy)
+	replace(&merged.Mode, override.Mode)
+	replace(&merged.Strict, override.Strict)
+	replace(&merged.BindCursor, override.BindCursor)
+	replace(&merged.RangeHeader, override.RangeHeader)
+	replace(&merged.RangeUnit, override.RangeUnit)
+	replace(&merged.Transparent, override.Transparent)
+	replace(&merged.Canonical, override.Canonical)
+	return merged
+}
+
+// replace sets *dst to *v unless v is nil.
+func replace[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
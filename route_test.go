@@ -0,0 +1,165 @@
+package spindle
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestMergeConfig(t *testing.T) {
+	t.Parallel()
+
+	base := configDefault(Config{
+		SortKey:      "sort",
+		AllowedSorts: []string{"id"},
+		Strict:       true,
+		MaxPage:      50,
+		Routes:       map[string]RouteConfig{"/a": {}},
+	})
+	merged := mergeConfig(base, RouteConfig{AllowedSorts: []string{"name"}, DefaultLimit: Ptr(5)})
+
+	if !reflect.DeepEqual(merged.AllowedSorts, []string{"name"}) || merged.DefaultLimit != 5 {
+		t.Errorf("AllowedSorts, DefaultLimit = %v, %d, want [name], 5", merged.AllowedSorts, merged.DefaultLimit)
+	}
+	if merged.SortKey != "sort" || !merged.Strict || merged.LimitKey != "limit" || merged.MaxPage != 50 {
+		t.Errorf("SortKey, Strict, LimitKey, MaxPage = %q, %v, %q, %d, want inherited", merged.SortKey, merged.Strict, merged.LimitKey, merged.MaxPage)
+	}
+	if merged.Routes != nil {
+		t.Errorf("Routes = %v, want nil", merged.Routes)
+	}
+
+	// Overrides reset fields to their zero value.
+	merged = mergeConfig(base, RouteConfig{Strict: Ptr(false), MaxPage: Ptr(0), AllowedSorts: []string{}})
+	if merged.Strict || merged.MaxPage != 0 || len(merged.AllowedSorts) != 0 {
+		t.Errorf("Strict, MaxPage, AllowedSorts = %v, %d, %v, want reset", merged.Strict, merged.MaxPage, merged.AllowedSorts)
+	}
+}
+
+func TestRouteConfigFields(t *testing.T) {
+	t.Parallel()
+
+	// Every Config field but Routes and BodyLimit can be overridden.
+	route := reflect.TypeFor[RouteConfig]()
+	config := reflect.TypeFor[Config]()
+	for i := range config.NumField() {
+		name := config.Field(i).Name
+		if name == "Routes" || name == "BodyLimit" {
+			continue
+		}
+		if _, ok := route.FieldByName(name); !ok {
+			t.Errorf("RouteConfig lacks %s", name)
+		}
+	}
+	if route.NumField() != config.NumField()-2 {
+		t.Errorf("RouteConfig has %d fields, want %d", route.NumField(), config.NumField()-2)
+	}
+}
+
+func Test_PaginateRoutes(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		SortKey:      "sort",
+		AllowedSorts: []string{"id"},
+		Strict:       true,
+		Routes: map[string]RouteConfig{
+			"/users/:id/posts": {AllowedSorts: []string{"title"}, DefaultSort: Ptr("title"), DefaultLimit: Ptr(5), Strict: Ptr(false)},
+			"articles":         {AllowedSorts: []string{"created_at"}, DefaultSort: Ptr("-created_at")},
+		},
+	}))
+
+	handler := func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		return c.JSON(pageInfo)
+	}
+	app.Get("/users/:id/posts", handler)
+	app.Get("/articles", handler).Name("articles")
+	app.Get("/other", handler)
+
+	testCases := []struct {
+		name  string
+		url   string
+		limit int
+		sort  []SortField
+	}{
+		{"Path override", "/users/7/posts?sort=title", 5, []SortField{{Field: "title", Order: ASC}}},
+		{"Path override ignores base sorts", "/users/7/posts?sort=id", 5, []SortField{{Field: "title", Order: ASC}}},
+		{"Named override", "/articles", 10, []SortField{{Field: "created_at", Order: DESC}}},
+		{"No override", "/other?sort=id", 10, []SortField{{Field: "id", Order: ASC}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.url, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pageInfo PageInfo
+			if err := json.NewDecoder(resp.Body).Decode(&pageInfo); err != nil {
+				t.Fatal(err)
+			}
+			if pageInfo.Limit != tc.limit || !reflect.DeepEqual(pageInfo.Sort, tc.sort) {
+				t.Errorf("Limit, Sort = %d, %v, want %d, %v", pageInfo.Limit, pageInfo.Sort, tc.limit, tc.sort)
+			}
+		})
+	}
+}
+
+func Test_PaginateRoutesPrecedence(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Routes: map[string]RouteConfig{
+			"/users/:id":         {DefaultLimit: Ptr(5)},
+			"/files/*":           {DefaultLimit: Ptr(7)},
+			"/teams/:team/posts": {DefaultLimit: Ptr(9)},
+		},
+	}))
+
+	handler := func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		return c.JSON(pageInfo)
+	}
+	app.Get("/users/new", handler)
+	app.Get("/users/:id", handler)
+	app.Get("/users/:id/posts", handler)
+	app.Get("/files/*", handler)
+
+	limit := func(url string) int {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pageInfo PageInfo
+		if err := json.NewDecoder(resp.Body).Decode(&pageInfo); err != nil {
+			t.Fatal(err)
+		}
+		return pageInfo.Limit
+	}
+
+	testCases := []struct {
+		url   string
+		limit int
+	}{
+		{"/users/new", 10},
+		{"/users/7", 5},
+		{"/Users/7/", 5},
+		{"/users/7/posts", 10},
+		{"/files/a/b.txt", 7},
+	}
+	for _, tc := range testCases {
+		if got := limit(tc.url); got != tc.limit {
+			t.Errorf("%s: Limit = %d, want %d", tc.url, got, tc.limit)
+		}
+	}
+
+	// Routes registered after the first request are picked up.
+	app.Get("/teams/:team/posts", handler)
+	if got := limit("/teams/red/posts"); got != 9 {
+		t.Errorf("late route: Limit = %d, want 9", got)
+	}
+}
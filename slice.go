@@ -0,0 +1,203 @@
+package spindle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Paginate sorts and windows an in-memory collection according to the
+// PageInfo. fields maps sort field names to comparison functions returning
+// a negative number, zero or a positive number as in slices.SortFunc; sort
+// fields without a comparator are skipped, and items that compare equal
+// keep their input order. items is not modified.
+//
+// The window starts at Start() in page and offset mode. In cursor mode the
+// cursor records the sort values of the last item of the page, and the
+// next page starts at the first item sorting after them, so items inserted
+// or removed meanwhile do not shift pages. Values are read from the
+// exported struct field named like the sort field, by its json name or,
+// case-insensitively, its Go name, and recording stops at the first sort
+// field without one. Items equal on the recorded values are told apart by
+// position, so the sort should end with a unique field such as
+// Config.Tiebreaker. Total, HasMore and NextCursor are filled in on the
+// PageInfo; HasMore is only set in cursor mode when the cursor could be
+// encoded.
+func Paginate[T any](items []T, p *PageInfo, fields map[string]func(a, b T) int) []T {
+	var sorts []sliceSort[T]
+	for _, field := range p.Sort {
+		if compare, ok := fields[field.Field]; ok {
+			sorts = append(sorts, sliceSort[T]{field: field, compare: compare})
+		}
+	}
+
+	sorted := slices.Clone(items)
+	if len(sorts) > 0 {
+		slices.SortStableFunc(sorted, func(a, b T) int { return compareSlice(sorts, a, b) })
+	}
+
+	total := len(sorted)
+	p.SetTotal(total)
+
+	cursorMode := p.Mode == ModeCursor || p.Cursor != ""
+	start := p.Start()
+	var seek []sliceSort[T]
+	if cursorMode {
+		seek = seekSorts(sorts)
+		start = 0
+		if probe, ties, ok := decodeSliceCursor(p.Cursor, seek); ok {
+			// Skip the items sorting before the recorded values, then the
+			// ties already served.
+			start, _ = slices.BinarySearchFunc(sorted, probe, func(item, probe T) int {
+				if compareSlice(seek, item, probe) < 0 {
+					return -1
+				}
+				return 1
+			})
+			for ; ties > 0 && start < total && compareSlice(seek, sorted[start], probe) == 0; ties-- {
+				start++
+			}
+		}
+	}
+	start = min(max(start, 0), total)
+
+	limit := p.Limit
+	if limit < 1 {
+		limit = total
+	}
+	end := min(start+limit, total)
+
+	p.HasMore = false
+	p.NextCursor = ""
+	if end < total {
+		if cursorMode {
+			// SetNextCursor sets HasMore once the cursor is encoded.
+			p.SetNextCursor(sliceCursor(sorted, end-1, seek))
+		} else {
+			p.HasMore = true
+		}
+	}
+
+	return sorted[start:end]
+}
+
+// sliceSort is a sort field of Paginate with its comparator and, for
+// cursors, the index of the struct field holding its value.
+type sliceSort[T any] struct {
+	field   SortField
+	compare func(a, b T) int
+	index   []int
+}
+
+func compareSlice[T any](sorts []sliceSort[T], a, b T) int {
+	for _, sort := range sorts {
+		if result := sort.compare(a, b); result != 0 {
+			if sort.field.Order == DESC {
+				return -result
+			}
+			return result
+		}
+	}
+	return 0
+}
+
+// seekSorts returns the leading sorts whose values can be read from T and
+// recorded in a cursor. Only a prefix of the sort keeps the order of the
+// sorted items when compared alone.
+func seekSorts[T any](sorts []sliceSort[T]) []sliceSort[T] {
+	typ := reflect.TypeFor[T]()
+	seek := make([]sliceSort[T], 0, len(sorts))
+	for _, sort := range sorts {
+		index, ok := sliceFieldIndex(typ, sort.field.Field)
+		if !ok {
+			break
+		}
+		sort.index = index
+		seek = append(seek, sort)
+	}
+	return seek
+}
+
+// sliceFieldIndex finds the exported field of struct type typ a sort field
+// reads, by json name or, case-insensitively, Go name. Fields promoted
+// through embedded pointers are not used, as they cannot be set on a zero
+// value.
+func sliceFieldIndex(typ reflect.Type, name string) ([]int, bool) {
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for _, field := range reflect.VisibleFields(typ) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName != name && !strings.EqualFold(field.Name, name) {
+			continue
+		}
+		embedded := typ
+		for _, i := range field.Index[:len(field.Index)-1] {
+			embedded = embedded.Field(i).Type
+			if embedded.Kind() != reflect.Struct {
+				return nil, false
+			}
+		}
+		return field.Index, true
+	}
+	return nil, false
+}
+
+// sliceCursor records the sort values of sorted[last] and how many items
+// up to it share them.
+func sliceCursor[T any](sorted []T, last int, seek []sliceSort[T]) map[string]any {
+	item := reflect.ValueOf(sorted[last])
+	values := make(map[string]any, len(seek))
+	for _, sort := range seek {
+		values[sort.field.Field] = item.FieldByIndex(sort.index).Interface()
+	}
+
+	ties := 1
+	for i := last - 1; i >= 0 && compareSlice(seek, sorted[i], sorted[last]) == 0; i-- {
+		ties++
+	}
+	return map[string]any{"after": values, "ties": ties}
+}
+
+// decodeSliceCursor rebuilds the values recorded by sliceCursor into a
+// probe item comparable with the sorted items.
+func decodeSliceCursor[T any](cursor string, seek []sliceSort[T]) (T, int, bool) {
+	var probe T
+	var decoded struct {
+		After map[string]json.RawMessage `json:"after"`
+		Ties  int                        `json:"ties"`
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || json.Unmarshal(data, &decoded) != nil {
+		return probe, 0, false
+	}
+
+	value := reflect.ValueOf(&probe).Elem()
+	for _, sort := range seek {
+		raw, ok := decoded.After[sort.field.Field]
+		if !ok || json.Unmarshal(raw, value.FieldByIndex(sort.index).Addr().Interface()) != nil {
+			return probe, 0, false
+		}
+	}
+	return probe, decoded.Ties, true
+}
+
+// TrimPage drops the lookahead row of a query that fetched Limit+1 rows in
+// cursor mode. When there is one, the next cursor is recorded from the last
+// row kept, using cursor to collect its values; HasMore is set accordingly.
+func TrimPage[T any](items []T, p *PageInfo, cursor func(last T) map[string]any) []T {
+	p.HasMore = false
+	p.NextCursor = ""
+	if p.Limit < 1 || len(items) <= p.Limit {
+		return items
+	}
+
+	items = items[:p.Limit]
+	p.SetNextCursor(cursor(items[len(items)-1]))
+	return items
+}
@@ -0,0 +1,266 @@
+package spindle
+
+import (
+	"cmp"
+	"encoding/base64"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type product struct {
+	ID    int
+	Name  string
+	Price int
+}
+
+var productFields = map[string]func(a, b product) int{
+	"id":    func(a, b product) int { return cmp.Compare(a.ID, b.ID) },
+	"name":  func(a, b product) int { return strings.Compare(a.Name, b.Name) },
+	"price": func(a, b product) int { return cmp.Compare(a.Price, b.Price) },
+}
+
+var products = []product{
+	{ID: 1, Name: "kettle", Price: 30},
+	{ID: 2, Name: "apron", Price: 15},
+	{ID: 3, Name: "mug", Price: 10},
+	{ID: 4, Name: "bowl", Price: 15},
+	{ID: 5, Name: "spoon", Price: 5},
+}
+
+func productIDs(items []product) []int {
+	ids := make([]int, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pageInfo *PageInfo
+		ids      []int
+		hasMore  bool
+	}{
+		{
+			"First page by id",
+			NewPageInfo(1, 2, 0, []SortField{{Field: "id", Order: ASC}}),
+			[]int{1, 2},
+			true,
+		},
+		{
+			"Last page",
+			NewPageInfo(3, 2, 0, []SortField{{Field: "id", Order: ASC}}),
+			[]int{5},
+			false,
+		},
+		{
+			"Descending",
+			NewPageInfo(1, 3, 0, []SortField{{Field: "id", Order: DESC}}),
+			[]int{5, 4, 3},
+			true,
+		},
+		{
+			"Multiple fields",
+			NewPageInfo(1, 5, 0, []SortField{{Field: "price", Order: DESC}, {Field: "name", Order: ASC}}),
+			[]int{1, 2, 4, 3, 5},
+			false,
+		},
+		{
+			"Offset",
+			NewPageInfo(1, 2, 2, []SortField{{Field: "name", Order: ASC}}),
+			[]int{1, 3},
+			true,
+		},
+		{
+			"Unknown field keeps order",
+			NewPageInfo(1, 5, 0, []SortField{{Field: "color", Order: ASC}}),
+			[]int{1, 2, 3, 4, 5},
+			false,
+		},
+		{
+			"Past the end",
+			NewPageInfo(10, 2, 0, nil),
+			[]int{},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Paginate(products, tt.pageInfo, productFields)
+
+			if ids := productIDs(result); !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("Paginate() ids = %v, want %v", ids, tt.ids)
+			}
+			if tt.pageInfo.HasMore != tt.hasMore {
+				t.Errorf("HasMore = %v, want %v", tt.pageInfo.HasMore, tt.hasMore)
+			}
+			if tt.pageInfo.NextCursor != "" {
+				t.Errorf("NextCursor = %q, want empty outside cursor mode", tt.pageInfo.NextCursor)
+			}
+			if tt.pageInfo.Total != len(products) {
+				t.Errorf("Total = %d, want %d", tt.pageInfo.Total, len(products))
+			}
+		})
+	}
+}
+
+func TestPaginateCursor(t *testing.T) {
+	t.Parallel()
+
+	sorts := []SortField{{Field: "price", Order: ASC}, {Field: "id", Order: ASC}}
+
+	var ids []int
+	cursor := ""
+	for range len(products) {
+		p := &PageInfo{Limit: 2, Sort: sorts, Mode: ModeCursor, Cursor: cursor}
+		ids = append(ids, productIDs(Paginate(products, p, productFields))...)
+		if !p.HasMore {
+			if p.NextCursor != "" {
+				t.Errorf("NextCursor = %q, want empty on the last page", p.NextCursor)
+			}
+			break
+		}
+		cursor = p.NextCursor
+	}
+
+	expected := []int{5, 3, 2, 4, 1}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("ids across pages = %v, want %v", ids, expected)
+	}
+}
+
+func TestPaginateCursorSurvivesInserts(t *testing.T) {
+	t.Parallel()
+
+	sorts := []SortField{{Field: "price", Order: ASC}, {Field: "id", Order: ASC}}
+
+	first := &PageInfo{Limit: 2, Sort: sorts, Mode: ModeCursor}
+	if ids := productIDs(Paginate(products, first, productFields)); !reflect.DeepEqual(ids, []int{5, 3}) {
+		t.Fatalf("first page ids = %v, want [5 3]", ids)
+	}
+
+	// An item sorting before the cursor must not shift the next page.
+	grown := append([]product{{ID: 6, Name: "cork", Price: 1}}, products...)
+	next := &PageInfo{Limit: 2, Sort: sorts, Mode: ModeCursor, Cursor: first.NextCursor}
+	if ids := productIDs(Paginate(grown, next, productFields)); !reflect.DeepEqual(ids, []int{2, 4}) {
+		t.Errorf("next page ids = %v, want [2 4]", ids)
+	}
+}
+
+func TestPaginateCursorRecordsSortValues(t *testing.T) {
+	t.Parallel()
+
+	type account struct {
+		ID       int    `json:"id"`
+		Password string `json:"password"`
+	}
+	accounts := []account{{1, "hunter2"}, {2, "swordfish"}}
+	fields := map[string]func(a, b account) int{
+		"id": func(a, b account) int { return cmp.Compare(a.ID, b.ID) },
+	}
+
+	p := &PageInfo{Limit: 1, Sort: []SortField{{Field: "id", Order: ASC}}, Mode: ModeCursor}
+	Paginate(accounts, p, fields)
+
+	data, err := base64.RawURLEncoding.DecodeString(p.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"after":{"id":1},"ties":1}` {
+		t.Errorf("cursor = %s, want only the sort values", data)
+	}
+}
+
+func TestPaginateCursorWithoutComparator(t *testing.T) {
+	t.Parallel()
+
+	// The default sort has no comparator, so items keep their input order
+	// and the cursor tells them apart by position.
+	fields := map[string]func(a, b product) int{"name": productFields["name"]}
+	sorts := []SortField{{Field: "id", Order: ASC}}
+
+	var ids []int
+	cursor := ""
+	for range len(products) {
+		p := &PageInfo{Limit: 2, Sort: sorts, Mode: ModeCursor, Cursor: cursor}
+		ids = append(ids, productIDs(Paginate(products, p, fields))...)
+		if !p.HasMore {
+			break
+		}
+		cursor = p.NextCursor
+	}
+
+	if expected := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("ids across pages = %v, want %v", ids, expected)
+	}
+}
+
+func TestPaginateCursorNotEncodable(t *testing.T) {
+	t.Parallel()
+
+	type score struct {
+		Value float64
+	}
+	fields := map[string]func(a, b score) int{
+		"value": func(a, b score) int { return cmp.Compare(a.Value, b.Value) },
+	}
+
+	p := &PageInfo{Limit: 1, Sort: []SortField{{Field: "value", Order: DESC}}, Mode: ModeCursor}
+	Paginate([]score{{1}, {math.Inf(1)}}, p, fields)
+
+	if p.HasMore || p.NextCursor != "" {
+		t.Errorf("HasMore, NextCursor = %v, %q, want no next page without a cursor", p.HasMore, p.NextCursor)
+	}
+}
+
+func TestPaginateDoesNotModifyInput(t *testing.T) {
+	t.Parallel()
+
+	items := []product{{ID: 2}, {ID: 1}}
+	Paginate(items, NewPageInfo(1, 10, 0, []SortField{{Field: "id", Order: ASC}}), productFields)
+
+	if items[0].ID != 2 {
+		t.Errorf("items[0].ID = %d, want 2; input was reordered", items[0].ID)
+	}
+}
+
+func TestTrimPage(t *testing.T) {
+	t.Parallel()
+
+	cursor := func(last product) map[string]any {
+		return map[string]any{"id": last.ID}
+	}
+
+	t.Run("Lookahead row present", func(t *testing.T) {
+		p := &PageInfo{Limit: 2}
+		result := TrimPage(products[:3], p, cursor)
+
+		if ids := productIDs(result); !reflect.DeepEqual(ids, []int{1, 2}) {
+			t.Errorf("TrimPage() ids = %v, want [1 2]", ids)
+		}
+		if !p.HasMore {
+			t.Error("HasMore = false, want true")
+		}
+		if values := (&PageInfo{Cursor: p.NextCursor}).CursorValues(); values["id"] != float64(2) {
+			t.Errorf("next cursor values = %v, want id 2", values)
+		}
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		p := &PageInfo{Limit: 5, HasMore: true, NextCursor: "stale"}
+		result := TrimPage(products[:3], p, cursor)
+
+		if len(result) != 3 {
+			t.Errorf("len(TrimPage()) = %d, want 3", len(result))
+		}
+		if p.HasMore || p.NextCursor != "" {
+			t.Errorf("HasMore, NextCursor = %v, %q, want false, empty", p.HasMore, p.NextCursor)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package spindle
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSnapshot is returned when a snapshot token is not valid.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+	// ErrSnapshotRequired is returned in strict mode when a page past the
+	// first is requested without a snapshot token.
+	ErrSnapshotRequired = errors.New("snapshot required past the first page")
+)
+
+// snapshotToken is the decoded form of a snapshot token.
+type snapshotToken struct {
+	At string `json:"at"`
+}
+
+// encodeSnapshot renders value as a token. With a secret the token is
+// signed with HMAC-SHA256, as payload.signature.
+func encodeSnapshot(value string, secret []byte) string {
+	data, _ := json.Marshal(snapshotToken{At: value})
+	token := base64.RawURLEncoding.EncodeToString(data)
+	if len(secret) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(signSnapshot(token, secret))
+	}
+	return token
+}
+
+// decodeSnapshot returns the value of a token. Signed tokens may carry any
+// value; without a secret only RFC 3339 timestamps, as issued by default,
+// are accepted, so the value is safe to compare against a column.
+func decodeSnapshot(token string, secret []byte) (string, error) {
+	payload, signature, signed := strings.Cut(token, ".")
+	if signed != (len(secret) > 0) {
+		return "", ErrInvalidSnapshot
+	}
+	if signed {
+		sum, err := base64.RawURLEncoding.DecodeString(signature)
+		if err != nil || !hmac.Equal(sum, signSnapshot(payload, secret)) {
+			return "", ErrInvalidSnapshot
+		}
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrInvalidSnapshot
+	}
+	var s snapshotToken
+	if err := json.Unmarshal(data, &s); err != nil || s.At == "" {
+		return "", ErrInvalidSnapshot
+	}
+	if signed {
+		return s.At, nil
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, s.At)
+	if err != nil {
+		return "", ErrInvalidSnapshot
+	}
+	return at.UTC().Format(time.RFC3339Nano), nil
+}
+
+func signSnapshot(payload string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// resolveSnapshot returns the snapshot a page or offset request is served
+// at: the one its token carries, or a new one for the first page. carried
+// reports whether the token came from the request.
+func resolveSnapshot(in Input, cfg Config, page, offset int, w *warnings) (snapshot string, carried bool, err error) {
+	if token := in.get(cfg.Sources, cfg.SnapshotKey); token != "" {
+		snapshot, err = decodeSnapshot(token, cfg.SnapshotSecret)
+		return snapshot, err == nil, err
+	}
+
+	snapshot = newSnapshot()
+	if page > 1 || offset > 0 {
+		if cfg.Strict {
+			return "", false, ErrSnapshotRequired
+		}
+		w.add(cfg.SnapshotKey, "", snapshot, "no snapshot past the first page, issuing a new one")
+	}
+	return snapshot, false, nil
+}
+
+// newSnapshot returns the snapshot issued to a request without one: the
+// current time in RFC 3339 format.
+func newSnapshot() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// SetSnapshot replaces the snapshot issued to this request, for instance
+// with the largest ID of the collection instead of the current time, and
+// updates SnapshotToken. Values other than RFC 3339 timestamps are only
+// accepted back when Config.SnapshotSecret signs the token. Chainable.
+func (p *PageInfo) SetSnapshot(value string) *PageInfo {
+	p.Snapshot = value
+	p.SnapshotToken = encodeSnapshot(value, p.snapshotSecret)
+	return p
+}
@@ -0,0 +1,213 @@
+package spindle
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	token := encodeSnapshot("2026-10-19T10:00:00+02:00", nil)
+	value, err := decodeSnapshot(token, nil)
+	if err != nil || value != "2026-10-19T08:00:00Z" {
+		t.Errorf("decodeSnapshot() = %q, %v, want 2026-10-19T08:00:00Z", value, err)
+	}
+
+	secret := []byte("secret")
+	signed := encodeSnapshot("1042", secret)
+	value, err = decodeSnapshot(signed, secret)
+	if err != nil || value != "1042" {
+		t.Errorf("decodeSnapshot() signed = %q, %v, want 1042", value, err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		secret []byte
+	}{
+		{"Not base64", "%%%", nil},
+		{"Not JSON", "bm90IGpzb24", nil},
+		{"Empty", encodeSnapshot("", nil), nil},
+		{"Not a timestamp", encodeSnapshot("1042", nil), nil},
+		{"Forged", encodeSnapshot("' OR 1=1 --", nil), nil},
+		{"Signed without secret", signed, nil},
+		{"Unsigned with secret", encodeSnapshot("2026-10-19T08:00:00Z", nil), secret},
+		{"Wrong secret", signed, []byte("other")},
+		{"Tampered", encodeSnapshot("1043", nil) + signed[strings.Index(signed, "."):], secret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := decodeSnapshot(tt.token, tt.secret); !errors.Is(err, ErrInvalidSnapshot) {
+				t.Errorf("decodeSnapshot(%q) error = %v, want %v", tt.token, err, ErrInvalidSnapshot)
+			}
+		})
+	}
+}
+
+func TestPageInfoKeySnapshot(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{Snapshot: true})
+	first, err := parser.Parse(Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(first.Key(), "snapshot") {
+		t.Errorf("Key() = %q, want no issued snapshot", first.Key())
+	}
+
+	key := func(at string) string {
+		t.Helper()
+		token := encodeSnapshot(at, nil)
+		pageInfo, err := parser.Parse(Input{Query: url.Values{"page": {"2"}, "snapshot": {token}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(pageInfo.Key(), "&snapshot="+token) {
+			t.Errorf("Key() = %q, want the carried snapshot", pageInfo.Key())
+		}
+		return pageInfo.Key()
+	}
+	if key("2026-10-19T08:00:00Z") == key("2026-10-19T09:00:00Z") {
+		t.Error("Key() is the same for different snapshots")
+	}
+}
+
+func TestParserParseSnapshot(t *testing.T) {
+	t.Parallel()
+
+	parser := NewParser(Config{Snapshot: true})
+
+	first, err := parser.Parse(Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, first.Snapshot); err != nil {
+		t.Errorf("Snapshot = %q, want an RFC 3339 timestamp", first.Snapshot)
+	}
+	if first.SnapshotToken == "" || len(first.Warnings) != 0 {
+		t.Errorf("SnapshotToken, Warnings = %q, %v, want a token and no warnings", first.SnapshotToken, first.Warnings)
+	}
+
+	next, err := parser.Parse(Input{Query: url.Values{"page": {"2"}, "snapshot": {first.SnapshotToken}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Snapshot != first.Snapshot || next.SnapshotToken != first.SnapshotToken {
+		t.Errorf("Snapshot = %q, want %q carried over", next.Snapshot, first.Snapshot)
+	}
+
+	missing, err := parser.Parse(Input{Query: url.Values{"page": {"2"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing.Snapshot == "" || len(missing.Warnings) != 1 || missing.Warnings[0].Param != "snapshot" {
+		t.Errorf("Snapshot, Warnings = %q, %v, want a new snapshot and a warning", missing.Snapshot, missing.Warnings)
+	}
+
+	cursor, err := parser.Parse(Input{Query: url.Values{"cursor": {"eyJpZCI6MX0"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Snapshot != "" {
+		t.Errorf("Snapshot = %q in cursor mode, want none", cursor.Snapshot)
+	}
+}
+
+func TestParserParseSnapshotErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		query   url.Values
+		wantErr error
+	}{
+		{"Invalid token", Config{Snapshot: true}, url.Values{"snapshot": {"%%%"}}, ErrInvalidSnapshot},
+		{"Strict page", Config{Snapshot: true, Strict: true}, url.Values{"page": {"2"}}, ErrSnapshotRequired},
+		{"Strict offset", Config{Snapshot: true, Strict: true}, url.Values{"offset": {"20"}}, ErrSnapshotRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := NewParser(tt.cfg).Parse(Input{Query: tt.query}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewParser(Config{Snapshot: true, Strict: true}).Parse(Input{}); err != nil {
+		t.Errorf("Parse() first page error = %v, want nil", err)
+	}
+}
+
+func Test_PaginateSnapshotURLs(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	secret := []byte("secret")
+	app.Use(New(Config{Snapshot: true, SnapshotKey: "as_of", SnapshotSecret: secret}))
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		if pageInfo.Page == 1 {
+			pageInfo.SetSnapshot("1042") // max ID instead of the current time
+		}
+		return c.JSON(fiber.Map{
+			"snapshot": pageInfo.Snapshot,
+			"info":     pageInfo,
+			"next":     pageInfo.NextPageURL("/"),
+			"previous": pageInfo.PreviousPageURL("/"),
+		})
+	})
+
+	get := func(url string) map[string]any {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", url, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	first := get("/")
+	token := encodeSnapshot("1042", secret)
+	if first["next"] != "/?page=2&limit=10&as_of="+token {
+		t.Errorf("next = %v", first["next"])
+	}
+	if first["info"].(map[string]any)["snapshot"] != token {
+		t.Errorf("info.snapshot = %v, want %s", first["info"].(map[string]any)["snapshot"], token)
+	}
+
+	second := get(first["next"].(string))
+	if second["snapshot"] != "1042" {
+		t.Errorf("snapshot = %v, want 1042", second["snapshot"])
+	}
+	if second["previous"] != "/?page=1&limit=10&as_of="+token {
+		t.Errorf("previous = %v", second["previous"])
+	}
+}
+
+func TestOpenAPIParametersSnapshot(t *testing.T) {
+	t.Parallel()
+
+	names := parameterNames(OpenAPIParameters(Config{Snapshot: true, Mode: ModePage}))
+	if !slices.Equal(names, []string{"page", "limit", "snapshot"}) {
+		t.Errorf("names = %v", names)
+	}
+}
@@ -0,0 +1,78 @@
+package spindle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// SortConflictPolicy decides how a field sorted in both directions, as in
+// sort=name,-name, is served.
+type SortConflictPolicy int
+
+const (
+	// SortFirstWins keeps the direction of the first occurrence.
+	SortFirstWins SortConflictPolicy = iota
+	// SortLastWins keeps the direction of the last occurrence.
+	SortLastWins
+	// SortReject rejects the request with 400.
+	SortReject
+)
+
+// ErrInvalidSort is returned when a sort repeats a field, sorts a field in
+// both directions under SortReject, or exceeds MaxSortFields in strict
+// mode.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// limitSorts removes repeated sort fields and applies MaxSortFields. A
+// repeated field keeps the position of its first occurrence. In strict
+// mode repetitions and extra fields are rejected instead of dropped;
+// otherwise each change is recorded in w.
+func limitSorts(cfg Config, sorts []SortField, w *warnings) ([]SortField, error) {
+	result := make([]SortField, 0, len(sorts))
+	seen := make(map[string]int, len(sorts))
+
+	for _, field := range sorts {
+		i, ok := seen[field.Field]
+		if !ok {
+			seen[field.Field] = len(result)
+			result = append(result, field)
+			continue
+		}
+
+		switch {
+		case result[i].Order == field.Order:
+			if cfg.Strict {
+				return nil, fmt.Errorf("%w: %q is sorted more than once", ErrInvalidSort, field.Field)
+			}
+			w.add(cfg.SortKey, sortParam(field), "", "sort field "+strconv.Quote(field.Field)+" is repeated")
+		case cfg.SortConflict == SortReject || cfg.Strict:
+			return nil, fmt.Errorf("%w: %q is sorted in both directions", ErrInvalidSort, field.Field)
+		case cfg.SortConflict == SortLastWins:
+			w.add(cfg.SortKey, sortParam(result[i]), sortParam(field), "sort field "+strconv.Quote(field.Field)+" is sorted in both directions")
+			result[i].Order = field.Order
+		default:
+			w.add(cfg.SortKey, sortParam(field), sortParam(result[i]), "sort field "+strconv.Quote(field.Field)+" is sorted in both directions")
+		}
+	}
+
+	if cfg.MaxSortFields > 0 && len(result) > cfg.MaxSortFields {
+		if cfg.Strict {
+			return nil, fmt.Errorf("%w: at most %d sort fields are allowed", ErrInvalidSort, cfg.MaxSortFields)
+		}
+		for _, field := range result[cfg.MaxSortFields:] {
+			w.add(cfg.SortKey, sortParam(field), "", "at most "+strconv.Itoa(cfg.MaxSortFields)+" sort fields are allowed")
+		}
+		result = result[:cfg.MaxSortFields]
+	}
+
+	return result, nil
+}
+
+// sortParam renders a sort field the way the sort query spells it.
+func sortParam(field SortField) string {
+	if field.Order == DESC {
+		return "-" + field.Field
+	}
+	return field.Field
+}
@@ -0,0 +1,92 @@
+package spindle
+
+import (
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestLimitSorts(t *testing.T) {
+	t.Parallel()
+
+	name := SortField{Field: "name", Order: ASC}
+	nameDesc := SortField{Field: "name", Order: DESC}
+	id := SortField{Field: "id", Order: ASC}
+	date := SortField{Field: "date", Order: DESC}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		sorts    []SortField
+		expected []SortField
+		wantErr  error
+	}{
+		{"Unique", Config{}, []SortField{name, id}, []SortField{name, id}, nil},
+		{"Duplicates collapsed", Config{}, []SortField{name, id, name, name}, []SortField{name, id}, nil},
+		{"First wins", Config{}, []SortField{name, id, nameDesc}, []SortField{name, id}, nil},
+		{"Last wins", Config{SortConflict: SortLastWins}, []SortField{name, id, nameDesc}, []SortField{nameDesc, id}, nil},
+		{"Reject conflict", Config{SortConflict: SortReject}, []SortField{name, nameDesc}, nil, ErrInvalidSort},
+		{"Reject allows duplicates", Config{SortConflict: SortReject}, []SortField{name, name}, []SortField{name}, nil},
+		{"Max fields", Config{MaxSortFields: 2}, []SortField{name, id, date}, []SortField{name, id}, nil},
+		{"Max fields after dedupe", Config{MaxSortFields: 2}, []SortField{name, name, id}, []SortField{name, id}, nil},
+		{"Strict duplicate", Config{Strict: true}, []SortField{name, name}, nil, ErrInvalidSort},
+		{"Strict conflict", Config{Strict: true, SortConflict: SortLastWins}, []SortField{name, nameDesc}, nil, ErrInvalidSort},
+		{"Strict max fields", Config{Strict: true, MaxSortFields: 2}, []SortField{name, id, date}, nil, ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := limitSorts(tt.cfg, tt.sorts, &warnings{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("limitSorts() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("limitSorts() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func Test_PaginateStrictSort(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		SortKey:       "sort",
+		AllowedSorts:  []string{"id", "name", "date"},
+		MaxSortFields: 2,
+		Strict:        true,
+	}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		return c.JSON(pageInfo)
+	})
+
+	testCases := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"Valid", "/?sort=name,-id", 200},
+		{"Repeated", "/?sort=name,name", 400},
+		{"Both directions", "/?sort=name,-name", 400},
+		{"Too many", "/?sort=name,id,date", 400},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.status)
+			}
+		})
+	}
+}
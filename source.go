@@ -0,0 +1,149 @@
+package spindle
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Source identifies where pagination parameters are read from.
+type Source int
+
+const (
+	// SourceQuery reads parameters from the query string.
+	SourceQuery Source = iota
+	// SourceBody reads parameters from a JSON request body.
+	SourceBody
+	// SourceForm reads parameters from a urlencoded or multipart form body.
+	SourceForm
+	// SourceHeader reads parameters from request headers named after the keys.
+	SourceHeader
+)
+
+// get returns the value of key from the first source that holds it.
+func (in Input) get(sources []Source, key string) string {
+	if key == "" {
+		return ""
+	}
+	for _, source := range sources {
+		if value := in.lookup(source, key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// getInt returns key as an integer, or def if it is missing. Malformed
+// values fall back to def and values below lowest are raised to it, with a
+// warning.
+func (in Input) getInt(sources []Source, key string, def, lowest int, w *warnings) int {
+	raw := in.get(sources, key)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.Atoi(raw)
+	switch {
+	case err != nil:
+		w.add(key, raw, strconv.Itoa(def), key+" must be an integer")
+		return def
+	case value < lowest:
+		w.add(key, raw, strconv.Itoa(lowest), key+" must be at least "+strconv.Itoa(lowest))
+		return lowest
+	}
+	return value
+}
+
+func (in Input) lookup(source Source, key string) string {
+	var values Values
+	switch source {
+	case SourceQuery:
+		return in.Query.Get(key)
+	case SourceBody:
+		values = in.Body
+	case SourceForm:
+		values = in.Form
+	case SourceHeader:
+		values = in.Header
+	}
+	if values == nil {
+		return ""
+	}
+	return values.Get(key)
+}
+
+// list returns every value of a source, for binding cursors. Only Values
+// that can be enumerated are listed: url.Values and those from JSONBody.
+func (in Input) list(source Source) url.Values {
+	var values Values
+	switch source {
+	case SourceQuery:
+		return in.Query
+	case SourceBody:
+		values = in.Body
+	case SourceForm:
+		values = in.Form
+	}
+
+	switch values := values.(type) {
+	case url.Values:
+		return values
+	case jsonValues:
+		list := make(url.Values, len(values))
+		for key, value := range values {
+			if s := bodyValue(value); s != "" {
+				list.Set(key, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// jsonValues holds the top-level fields of a decoded JSON object.
+type jsonValues map[string]any
+
+// Get returns the field rendered as it would appear in a query string.
+func (v jsonValues) Get(key string) string {
+	return bodyValue(v[key])
+}
+
+// JSONBody decodes a JSON object request body into Values for Input.Body.
+// Bodies that are not JSON objects yield no values, leaving the handler to
+// report them.
+func JSONBody(data []byte) Values {
+	var values jsonValues
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return jsonValues(nil)
+	}
+	return values
+}
+
+// bodyValue renders a decoded JSON value the way it would appear in a
+// query string. Arrays are joined with commas, so "sort": ["name", "-id"]
+// is read like sort=name,-id.
+func bodyValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := bodyValue(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ",")
+	default:
+		return ""
+	}
+}
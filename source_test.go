@@ -0,0 +1,246 @@
+package spindle
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestBodyValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"String", "abc", "abc"},
+		{"Number", json.Number("25"), "25"},
+		{"Bool", true, "true"},
+		{"Array", []any{"name", "-id"}, "name,-id"},
+		{"Nested object", map[string]any{"a": 1}, ""},
+		{"Missing", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bodyValue(tt.value); got != tt.expected {
+				t.Errorf("bodyValue(%v) = %q, want %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func newSourcesApp(sources ...Source) *fiber.App {
+	app := fiber.New()
+	app.Use(New(Config{
+		SortKey:      "sort",
+		DefaultSort:  "id",
+		AllowedSorts: []string{"id", "name"},
+		Sources:      sources,
+	}))
+
+	handler := func(c fiber.Ctx) error {
+		pageInfo, ok := FromContext(c)
+		if !ok {
+			return fiber.ErrBadRequest
+		}
+		return c.JSON(pageInfo)
+	}
+	app.Get("/search", handler)
+	app.Post("/search", handler)
+	return app
+}
+
+func Test_PaginateSourceJSONBody(t *testing.T) {
+	t.Parallel()
+	app := newSourcesApp(SourceBody)
+
+	body := `{"page": 3, "limit": 25, "sort": ["name", "-id"], "query": "shoes"}`
+	req := httptest.NewRequest("POST", "/search", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result PageInfo
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Page != 3 {
+		t.Errorf("Page = %d, want 3", result.Page)
+	}
+	if result.Limit != 25 {
+		t.Errorf("Limit = %d, want 25", result.Limit)
+	}
+	expectedSort := []SortField{{Field: "name", Order: ASC}, {Field: "id", Order: DESC}}
+	if !reflect.DeepEqual(result.Sort, expectedSort) {
+		t.Errorf("Sort = %v, want %v", result.Sort, expectedSort)
+	}
+}
+
+func Test_PaginateSourceJSONBodyCursor(t *testing.T) {
+	t.Parallel()
+	app := newSourcesApp(SourceBody)
+
+	body := `{"cursor": "not-valid!!!"}`
+	req := httptest.NewRequest("POST", "/search", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("status = %d, want %d for invalid body cursor", resp.StatusCode, fiber.StatusBadRequest)
+	}
+}
+
+func Test_PaginateSourcePrecedence(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		sources  []Source
+		wantPage int
+		wantSize int
+	}{
+		// The query string holds page=2, the body page=5 and limit=30
+		{"Query first", []Source{SourceQuery, SourceBody}, 2, 30},
+		{"Body first", []Source{SourceBody, SourceQuery}, 5, 30},
+		{"Query only", []Source{SourceQuery}, 2, 10},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			app := newSourcesApp(tc.sources...)
+
+			req := httptest.NewRequest("POST", "/search?page=2", strings.NewReader(`{"page": 5, "limit": 30}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var result PageInfo
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Page != tc.wantPage {
+				t.Errorf("Page = %d, want %d", result.Page, tc.wantPage)
+			}
+			if result.Limit != tc.wantSize {
+				t.Errorf("Limit = %d, want %d", result.Limit, tc.wantSize)
+			}
+		})
+	}
+}
+
+func Test_PaginateSourceInvalidJSONBody(t *testing.T) {
+	t.Parallel()
+	app := newSourcesApp(SourceBody)
+
+	req := httptest.NewRequest("POST", "/search", strings.NewReader(`{"page": `))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result PageInfo
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Page != 1 || result.Limit != 10 {
+		t.Errorf("Page, Limit = %d, %d, want defaults 1, 10", result.Page, result.Limit)
+	}
+}
+
+func Test_PaginateSourceForm(t *testing.T) {
+	t.Parallel()
+	app := newSourcesApp(SourceForm)
+
+	t.Run("urlencoded", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/search", strings.NewReader("page=4&limit=15&sort=-name"))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result PageInfo
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Page != 4 || result.Limit != 15 {
+			t.Errorf("Page, Limit = %d, %d, want 4, 15", result.Page, result.Limit)
+		}
+		expectedSort := []SortField{{Field: "name", Order: DESC}}
+		if !reflect.DeepEqual(result.Sort, expectedSort) {
+			t.Errorf("Sort = %v, want %v", result.Sort, expectedSort)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		if err := w.WriteField("page", "6"); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest("POST", "/search", &buf)
+		req.Header.Set(fiber.HeaderContentType, w.FormDataContentType())
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result PageInfo
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		if result.Page != 6 {
+			t.Errorf("Page = %d, want 6", result.Page)
+		}
+	})
+}
+
+func Test_PaginateSourceHeader(t *testing.T) {
+	t.Parallel()
+	app := newSourcesApp(SourceHeader, SourceQuery)
+
+	req := httptest.NewRequest("GET", "/search?page=2&limit=40", nil)
+	req.Header.Set("Limit", "20")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result PageInfo
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Page != 2 {
+		t.Errorf("Page = %d, want 2 from query", result.Page)
+	}
+	if result.Limit != 20 {
+		t.Errorf("Limit = %d, want 20 from header", result.Limit)
+	}
+}
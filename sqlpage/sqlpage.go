@@ -0,0 +1,229 @@
+// Package sqlpage runs queries paginated by a spindle PageInfo on
+// database/sql or pgx, including keyset pagination in cursor mode.
+package sqlpage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mutantkeyboard/spindle"
+	"github.com/mutantkeyboard/spindle/keyset"
+)
+
+var (
+	// errNoCursor is returned in cursor mode when Query.Cursor is nil.
+	errNoCursor = errors.New("sqlpage: Query.Cursor is required in cursor mode")
+
+	// errQualifiedColumn is returned for a sort field naming a table, such
+	// as users.created_at, which the subquery wrapping Query.SQL hides.
+	errQualifiedColumn = errors.New("sqlpage: sort fields must name result columns of Query.SQL, not table columns")
+)
+
+// Dialect describes how a database spells bind parameters and identifiers.
+type Dialect struct {
+	// Placeholder returns the n-th bind parameter, counting from 1.
+	Placeholder func(n int) string
+
+	// Quote escapes an identifier.
+	Quote func(name string) string
+}
+
+var (
+	// SQLite uses ? parameters and double-quoted identifiers.
+	SQLite = Dialect{Placeholder: questionMark, Quote: doubleQuote}
+
+	// Postgres uses $n parameters and double-quoted identifiers, for
+	// database/sql drivers and pgx alike.
+	Postgres = Dialect{Placeholder: dollar, Quote: doubleQuote}
+
+	// MySQL uses ? parameters and backquoted identifiers.
+	MySQL = Dialect{Placeholder: questionMark, Quote: backquote}
+)
+
+func questionMark(int) string {
+	return "?"
+}
+
+func dollar(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func doubleQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func backquote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Scanner reads the columns of the current row. *sql.Rows and pgx.Rows
+// both satisfy it.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// Rows iterates over a query result. *sql.Rows and pgx.Rows both satisfy it.
+type Rows interface {
+	Scanner
+	Next() bool
+	Err() error
+}
+
+// Querier runs a database/sql query. *sql.DB, *sql.Conn and *sql.Tx all
+// satisfy it.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Query is a base query paginated by a PageInfo.
+type Query[T any] struct {
+	// Dialect defaults to SQLite.
+	Dialect Dialect
+
+	// SQL is the base query without ORDER BY, LIMIT or OFFSET. It is wrapped
+	// in a subquery, so sort fields name its result columns, such as
+	// created_at; table-qualified names like users.created_at are rejected.
+	SQL string
+
+	// Args are the bind arguments of SQL.
+	Args []any
+
+	// Scan reads one row into an item.
+	Scan func(row Scanner) (T, error)
+
+	// Cursor collects the cursor values of the last item of a page, keyed
+	// by sort field. It is required in cursor mode.
+	Cursor func(last T) map[string]any
+}
+
+// Build renders the paginated query and its bind arguments. Page and offset
+// mode add LIMIT and OFFSET. Cursor mode adds a keyset condition derived
+// from the sort fields and the cursor, and fetches Limit+1 rows so Collect
+// can tell whether there is a next page.
+func (q Query[T]) Build(p *spindle.PageInfo) (string, []any, error) {
+	dialect := q.Dialect
+	if dialect.Placeholder == nil || dialect.Quote == nil {
+		dialect = SQLite
+	}
+	for _, field := range p.Sort {
+		if strings.Contains(field.Field, ".") {
+			return "", nil, fmt.Errorf("%w: %q", errQualifiedColumn, field.Field)
+		}
+	}
+
+	args := slices.Clone(q.Args)
+	placeholder := func() string {
+		return dialect.Placeholder(len(args) + 1)
+	}
+
+	var b strings.Builder
+	b.WriteString("SELECT * FROM (")
+	b.WriteString(q.SQL)
+	b.WriteString(") AS spindle_page")
+
+	cursorMode := keyset.IsCursor(p)
+	if cursorMode {
+		if q.Cursor == nil {
+			return "", nil, errNoCursor
+		}
+
+		columns, err := keyset.Columns(p)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(columns) > 0 {
+			// Number the keyset placeholders after the base query's.
+			n := len(args)
+			predicate, keysetArgs := keyset.SQL(columns, dialect.Quote, func() string {
+				n++
+				return dialect.Placeholder(n)
+			})
+			b.WriteString(" WHERE ")
+			b.WriteString(predicate)
+			args = append(args, keysetArgs...)
+		}
+	}
+
+	if len(p.Sort) > 0 {
+		b.WriteString(" ORDER BY ")
+		for i, field := range p.Sort {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(dialect.Quote(field.Field))
+			if field.Order == spindle.DESC {
+				b.WriteString(" DESC")
+			} else {
+				b.WriteString(" ASC")
+			}
+		}
+	}
+
+	limit := p.Limit
+	if cursorMode {
+		limit++
+	}
+	b.WriteString(" LIMIT ")
+	b.WriteString(placeholder())
+	args = append(args, limit)
+
+	if !cursorMode {
+		b.WriteString(" OFFSET ")
+		b.WriteString(placeholder())
+		args = append(args, p.Start())
+	}
+
+	return b.String(), args, nil
+}
+
+// Collect scans the rows returned for Build's query. In cursor mode it drops
+// the lookahead row and sets HasMore and NextCursor on the PageInfo. Collect
+// does not close rows, so it works with pgx:
+//
+//	query, args, err := q.Build(pageInfo)
+//	rows, err := pool.Query(ctx, query, args...)
+//	defer rows.Close()
+//	items, err := q.Collect(rows, pageInfo)
+func (q Query[T]) Collect(rows Rows, p *spindle.PageInfo) ([]T, error) {
+	var items []T
+	for rows.Next() {
+		item, err := q.Scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !keyset.IsCursor(p) {
+		return items, nil
+	}
+	if q.Cursor == nil {
+		return nil, errNoCursor
+	}
+	return spindle.TrimPage(items, p, q.Cursor), nil
+}
+
+// Fetch builds and runs the query on a database/sql handle and collects
+// the page.
+func (q Query[T]) Fetch(ctx context.Context, db Querier, p *spindle.PageInfo) ([]T, error) {
+	query, args, err := q.Build(p)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+
+	return q.Collect(rows, p)
+}
@@ -0,0 +1,204 @@
+package sqlpage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/mutantkeyboard/spindle"
+)
+
+type event struct {
+	ID       int
+	Kind     string
+	Priority int
+}
+
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+
+	_, err = db.Exec(`
+		CREATE TABLE events (id INTEGER PRIMARY KEY, kind TEXT, priority INTEGER);
+		INSERT INTO events (id, kind, priority) VALUES
+			(1, 'deploy', 2),
+			(2, 'alert', 1),
+			(3, 'deploy', 2),
+			(4, 'alert', 3),
+			(5, 'deploy', 2),
+			(6, 'audit', 1);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+var eventQuery = Query[event]{
+	SQL:  "SELECT id, kind, priority FROM events WHERE kind != ?",
+	Args: []any{"audit"},
+	Scan: func(row Scanner) (event, error) {
+		var e event
+		err := row.Scan(&e.ID, &e.Kind, &e.Priority)
+		return e, err
+	},
+	Cursor: func(last event) map[string]any {
+		return map[string]any{"priority": last.Priority, "id": last.ID}
+	},
+}
+
+func eventIDs(events []event) []int {
+	ids := make([]int, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	cursor := (&spindle.PageInfo{}).SetNextCursor(map[string]any{"priority": 2, "id": 3}).NextCursor
+	p := &spindle.PageInfo{
+		Limit:  10,
+		Sort:   []spindle.SortField{{Field: "priority", Order: spindle.DESC}, {Field: "id", Order: spindle.ASC}},
+		Mode:   spindle.ModeCursor,
+		Cursor: cursor,
+	}
+
+	q := eventQuery
+	q.Dialect = Postgres
+	q.SQL = "SELECT id, kind, priority FROM events WHERE kind != $1"
+
+	query, args, err := q.Build(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `SELECT * FROM (SELECT id, kind, priority FROM events WHERE kind != $1) AS spindle_page` +
+		` WHERE ("priority" < $2 OR ("priority" = $3 AND "id" > $4))` +
+		` ORDER BY "priority" DESC, "id" ASC LIMIT $5`
+	if query != expected {
+		t.Errorf("Build() query = %s, want %s", query, expected)
+	}
+	if !reflect.DeepEqual(args, []any{"audit", int64(2), int64(2), int64(3), 11}) {
+		t.Errorf("Build() args = %v", args)
+	}
+}
+
+func TestBuildOffset(t *testing.T) {
+	t.Parallel()
+
+	q := eventQuery
+	q.Dialect = MySQL
+
+	query, args, err := q.Build(spindle.NewPageInfo(3, 20, 0, []spindle.SortField{{Field: "kind", Order: spindle.ASC}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT * FROM (SELECT id, kind, priority FROM events WHERE kind != ?) AS spindle_page ORDER BY `kind` ASC LIMIT ? OFFSET ?"
+	if query != expected {
+		t.Errorf("Build() query = %s, want %s", query, expected)
+	}
+	if !reflect.DeepEqual(args, []any{"audit", 20, 40}) {
+		t.Errorf("Build() args = %v, want [audit 20 40]", args)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Missing cursor func", func(t *testing.T) {
+		q := eventQuery
+		q.Cursor = nil
+		if _, _, err := q.Build(&spindle.PageInfo{Limit: 5, Mode: spindle.ModeCursor}); !errors.Is(err, errNoCursor) {
+			t.Errorf("Build() error = %v, want %v", err, errNoCursor)
+		}
+	})
+
+	t.Run("Table-qualified sort field", func(t *testing.T) {
+		p := spindle.NewPageInfo(1, 5, 0, []spindle.SortField{{Field: "events.kind", Order: spindle.ASC}})
+		if _, _, err := eventQuery.Build(p); !errors.Is(err, errQualifiedColumn) {
+			t.Errorf("Build() error = %v, want %v", err, errQualifiedColumn)
+		}
+	})
+
+	t.Run("Cursor missing a sort field", func(t *testing.T) {
+		cursor := (&spindle.PageInfo{}).SetNextCursor(map[string]any{"id": 3}).NextCursor
+		p := &spindle.PageInfo{
+			Limit:  5,
+			Sort:   []spindle.SortField{{Field: "kind", Order: spindle.ASC}},
+			Cursor: cursor,
+		}
+		if _, _, err := eventQuery.Build(p); !errors.Is(err, spindle.ErrCursorMismatch) {
+			t.Errorf("Build() error = %v, want %v", err, spindle.ErrCursorMismatch)
+		}
+	})
+}
+
+func TestFetchOffset(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	p := spindle.NewPageInfo(2, 2, 0, []spindle.SortField{{Field: "id", Order: spindle.DESC}})
+	events, err := eventQuery.Fetch(context.Background(), db, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := eventIDs(events); !reflect.DeepEqual(ids, []int{3, 2}) {
+		t.Errorf("ids = %v, want [3 2]", ids)
+	}
+}
+
+func TestFetchCursor(t *testing.T) {
+	t.Parallel()
+	db := newDB(t)
+
+	sort := []spindle.SortField{{Field: "priority", Order: spindle.DESC}, {Field: "id", Order: spindle.ASC}}
+
+	var ids []int
+	cursor := ""
+	for range 5 {
+		p := &spindle.PageInfo{Limit: 2, Sort: sort, Mode: spindle.ModeCursor, Cursor: cursor}
+
+		events, err := eventQuery.Fetch(context.Background(), db, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, eventIDs(events)...)
+
+		if !p.HasMore {
+			if p.NextCursor != "" {
+				t.Errorf("NextCursor = %q, want empty on the last page", p.NextCursor)
+			}
+			break
+		}
+		cursor = p.NextCursor
+	}
+
+	expected := []int{4, 1, 3, 5, 2}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("ids across pages = %v, want %v", ids, expected)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	if got := doubleQuote(`a"b`); got != `"a""b"` {
+		t.Errorf("doubleQuote() = %s, want %s", got, `"a""b"`)
+	}
+	if got := backquote("a`b"); got != "`a``b`" {
+		t.Errorf("backquote() = %s, want %s", got, "`a``b`")
+	}
+}
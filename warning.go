@@ -0,0 +1,127 @@
+package spindle
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Warning records a request parameter the parser changed instead of
+// rejecting, such as a limit clamped to the maximum or a sort field that is
+// not allowed.
+type Warning struct {
+	// Param is the key of the parameter, or "Range" for the Range header.
+	Param string `json:"param"`
+
+	// Original is the value the client sent.
+	Original string `json:"original"`
+
+	// Effective is the value the request is served with, empty when the
+	// value was dropped.
+	Effective string `json:"effective,omitempty"`
+
+	// Message explains the change.
+	Message string `json:"message"`
+}
+
+// header renders the warning as an RFC 7234 Warning header value with the
+// 199 miscellaneous warning code.
+func (w Warning) header() string {
+	text := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(w.Message)
+	return `199 - "` + text + `"`
+}
+
+// maxWarningHeaders caps the Warning headers of one response, so a long
+// list of rejected sort fields cannot inflate the response headers.
+const maxWarningHeaders = 10
+
+// warningHeaders renders the Warning header values for list, one per
+// distinct param and message. Past maxWarningHeaders the rest are
+// summarized in a last header.
+func warningHeaders(list []Warning) []string {
+	type key struct{ param, message string }
+	seen := make(map[key]bool, len(list))
+	var headers []string
+	for _, warning := range list {
+		k := key{warning.Param, warning.Message}
+		if !seen[k] {
+			seen[k] = true
+			headers = append(headers, warning.header())
+		}
+	}
+
+	if len(headers) > maxWarningHeaders {
+		omitted := len(headers) - maxWarningHeaders + 1
+		headers = append(headers[:maxWarningHeaders-1],
+			Warning{Message: strconv.Itoa(omitted) + " more pagination warnings omitted"}.header())
+	}
+	return headers
+}
+
+// warnings collects the Warnings of one Parse call.
+type warnings []Warning
+
+func (w *warnings) add(param, original, effective, message string) {
+	*w = append(*w, Warning{Param: param, Original: original, Effective: effective, Message: message})
+}
+
+// depth records the changes limitDepth made to a page or offset request.
+func (w *warnings) depth(cfg Config, requested, mode Mode, requestedPage, page, requestedOffset, offset int) {
+	key, original := cfg.PageKey, requestedPage
+	if requestedOffset > 0 {
+		key, original = cfg.OffsetKey, requestedOffset
+	}
+
+	switch {
+	case mode != requested:
+		w.add(key, strconv.Itoa(original), "", "pagination depth exceeded, serving the first page in cursor mode")
+	case page != requestedPage:
+		w.add(cfg.PageKey, strconv.Itoa(requestedPage), strconv.Itoa(page), "pagination depth exceeded, serving the deepest allowed page")
+	case offset != requestedOffset:
+		w.add(cfg.OffsetKey, strconv.Itoa(requestedOffset), strconv.Itoa(offset), "pagination depth exceeded, serving the deepest allowed offset")
+	}
+}
+
+// ignored records the pagination parameters that do not belong to mode,
+// which the parser drops instead of rejecting outside strict mode.
+func (w *warnings) ignored(in Input, cfg Config, mode Mode, ranged bool) {
+	params := []struct {
+		mode Mode
+		key  string
+	}{
+		{ModeCursor, cfg.CursorKey},
+		{ModeCursor, cfg.CursorParam},
+		{ModePage, cfg.PageKey},
+		{ModeOffset, cfg.OffsetKey},
+	}
+	for _, param := range params {
+		if param.mode == mode || param.key == "" {
+			continue
+		}
+		if value := in.get(cfg.Sources, param.key); value != "" {
+			w.add(param.key, value, "", param.key+" is ignored in "+string(mode)+" mode")
+		}
+	}
+	if ranged && mode != ModeOffset {
+		w.add("Range", in.Header.Get("Range"), "", "Range is ignored in "+string(mode)+" mode")
+	}
+}
+
+// log writes each warning to logger, if any, with the request context.
+func (w warnings) log(ctx context.Context, logger *slog.Logger) {
+	if logger == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, warning := range w {
+		logger.LogAttrs(ctx, slog.LevelInfo, "pagination parameter coerced",
+			slog.String("param", warning.Param),
+			slog.String("original", warning.Original),
+			slog.String("effective", warning.Effective),
+			slog.String("reason", warning.Message),
+		)
+	}
+}
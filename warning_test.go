@@ -0,0 +1,282 @@
+package spindle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestParserParseWarnings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		in       Input
+		expected []Warning
+	}{
+		{
+			name: "None",
+			in:   Input{Query: url.Values{"page": {"2"}, "limit": {"20"}}},
+		},
+		{
+			name:     "Limit clamped",
+			in:       Input{Query: url.Values{"limit": {"500"}}},
+			expected: []Warning{{"limit", "500", "100", "limit exceeds the maximum of 100"}},
+		},
+		{
+			name:     "Malformed limit",
+			in:       Input{Query: url.Values{"limit": {"ten"}}},
+			expected: []Warning{{"limit", "ten", "10", "limit must be a positive integer"}},
+		},
+		{
+			name:     "Page reset",
+			in:       Input{Query: url.Values{"page": {"-3"}}},
+			expected: []Warning{{"page", "-3", "1", "page must be at least 1"}},
+		},
+		{
+			name:     "Malformed offset",
+			in:       Input{Query: url.Values{"offset": {"x"}}},
+			expected: []Warning{{"offset", "x", "0", "offset must be an integer"}},
+		},
+		{
+			name: "Sort fields",
+			cfg:  Config{SortKey: "sort", AllowedSorts: []string{"id", "name"}, MaxSortFields: 1},
+			in:   Input{Query: url.Values{"sort": {"-secret,name,-name,id"}}},
+			expected: []Warning{
+				{"sort", "-secret", "", `sort field "secret" is not allowed`},
+				{"sort", "-name", "name", `sort field "name" is sorted in both directions`},
+				{"sort", "id", "", "at most 1 sort fields are allowed"},
+			},
+		},
+		{
+			name:     "Depth clamped",
+			cfg:      Config{MaxPage: 5},
+			in:       Input{Query: url.Values{"page": {"9"}}},
+			expected: []Warning{{"page", "9", "5", "pagination depth exceeded, serving the deepest allowed page"}},
+		},
+		{
+			name:     "Depth switched to cursor",
+			cfg:      Config{MaxOffset: 100, DepthPolicy: DepthCursor},
+			in:       Input{Query: url.Values{"offset": {"500"}}},
+			expected: []Warning{{"offset", "500", "", "pagination depth exceeded, serving the first page in cursor mode"}},
+		},
+		{
+			name:     "Range clamped",
+			cfg:      Config{RangeHeader: true},
+			in:       Input{Header: http.Header{"Range": {"items=0-499"}}},
+			expected: []Warning{{"Range", "items=0-499", "100", "range exceeds the maximum of 100 items"}},
+		},
+		{
+			name:     "Page ignored by offset",
+			in:       Input{Query: url.Values{"page": {"3"}, "offset": {"5"}}},
+			expected: []Warning{{"page", "3", "", "page is ignored in offset mode"}},
+		},
+		{
+			name:     "Cursor ignored by page mode",
+			cfg:      Config{Mode: ModePage},
+			in:       Input{Query: url.Values{"cursor": {"eyJpZCI6MX0"}}},
+			expected: []Warning{{"cursor", "eyJpZCI6MX0", "", "cursor is ignored in page mode"}},
+		},
+		{
+			name:     "Range ignored by page mode",
+			cfg:      Config{Mode: ModePage, RangeHeader: true},
+			in:       Input{Header: http.Header{"Range": {"items=0-9"}}},
+			expected: []Warning{{"Range", "items=0-9", "", "Range is ignored in page mode"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pageInfo, err := NewParser(tt.cfg).Parse(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pageInfo.Warnings, tt.expected) {
+				t.Errorf("Warnings = %v, want %v", pageInfo.Warnings, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParserParseLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	parser := NewParser(Config{Logger: slog.New(slog.NewJSONHandler(&buf, nil))})
+
+	if _, err := parser.Parse(Input{Query: url.Values{"limit": {"500"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log output %q: %v", buf.String(), err)
+	}
+	expected := map[string]any{
+		"msg":       "pagination parameter coerced",
+		"param":     "limit",
+		"original":  "500",
+		"effective": "100",
+		"reason":    "limit exceeds the maximum of 100",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+}
+
+// contextHandler records the request ID found in the context of each log
+// record.
+type contextHandler struct {
+	slog.Handler
+	ids chan any
+}
+
+type requestIDKey struct{}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.ids <- ctx.Value(requestIDKey{})
+	return nil
+}
+
+func TestNewHTTPLoggerContext(t *testing.T) {
+	t.Parallel()
+
+	ids := make(chan any, 1)
+	logger := slog.New(contextHandler{Handler: slog.NewTextHandler(io.Discard, nil), ids: ids})
+	handler := NewHTTP(Config{Logger: logger})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/?limit=500", nil)
+	req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, "req-1"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if id := <-ids; id != "req-1" {
+		t.Errorf("logged request ID = %v, want req-1", id)
+	}
+}
+
+func TestWarningHeader(t *testing.T) {
+	t.Parallel()
+
+	w := Warning{Message: `sort field "a\b" is not allowed`}
+	if got, expected := w.header(), `199 - "sort field \"a\\b\" is not allowed"`; got != expected {
+		t.Errorf("header() = %s, want %s", got, expected)
+	}
+}
+
+func TestWarningHeaders(t *testing.T) {
+	t.Parallel()
+
+	repeated := Warning{Param: "sort", Original: "a", Message: `sort field "a" is not allowed`}
+	if got := warningHeaders([]Warning{repeated, repeated, repeated}); len(got) != 1 {
+		t.Errorf("repeated warnings = %v, want one header", got)
+	}
+
+	var many []Warning
+	for i := range maxWarningHeaders + 5 {
+		many = append(many, Warning{Param: "sort", Message: "field " + strconv.Itoa(i)})
+	}
+	got := warningHeaders(many)
+	if len(got) != maxWarningHeaders {
+		t.Fatalf("headers = %d, want %d", len(got), maxWarningHeaders)
+	}
+	if expected := `199 - "6 more pagination warnings omitted"`; got[len(got)-1] != expected {
+		t.Errorf("last header = %s, want %s", got[len(got)-1], expected)
+	}
+
+	many = many[:maxWarningHeaders]
+	if got := warningHeaders(many); len(got) != maxWarningHeaders || got[len(got)-1] != many[len(many)-1].header() {
+		t.Errorf("headers at the cap = %v, want every warning", got)
+	}
+}
+
+func Test_PaginateTransparentHeaderCap(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(New(Config{SortKey: "sort", AllowedSorts: []string{"id"}, MaxSortFields: 1, Transparent: true}))
+	app.Get("/", func(c fiber.Ctx) error {
+		pageInfo, _ := FromContext(c)
+		return c.JSON(NewEnvelope([]int{}, pageInfo))
+	})
+
+	sort := "id" + strings.Repeat(",x", 50)
+	resp, err := app.Test(httptest.NewRequest("GET", "/?sort="+sort, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fifty copies of one disallowed field share a single header.
+	expected := []string{`199 - "sort field \"x\" is not allowed"`}
+	if got := resp.Header.Values("Warning"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Warning = %v, want %v", got, expected)
+	}
+}
+
+func Test_PaginateTransparent(t *testing.T) {
+	t.Parallel()
+
+	for _, transparent := range []bool{true, false} {
+		app := fiber.New()
+		app.Use(New(Config{SortKey: "sort", AllowedSorts: []string{"id"}, Transparent: transparent}))
+		app.Get("/", func(c fiber.Ctx) error {
+			pageInfo, _ := FromContext(c)
+			return c.JSON(NewEnvelope([]int{}, pageInfo))
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/?limit=500&sort=secret", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		headers := resp.Header.Values("Warning")
+		var envelope struct {
+			Meta *Meta `json:"meta"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal(err)
+		}
+
+		if !transparent {
+			if len(headers) != 0 || envelope.Meta != nil {
+				t.Errorf("Warning = %v, meta = %v, want none", headers, envelope.Meta)
+			}
+			continue
+		}
+
+		expected := []string{`199 - "limit exceeds the maximum of 100"`, `199 - "sort field \"secret\" is not allowed"`}
+		if !reflect.DeepEqual(headers, expected) {
+			t.Errorf("Warning = %v, want %v", headers, expected)
+		}
+		if envelope.Meta == nil || len(envelope.Meta.Warnings) != 2 || envelope.Meta.Warnings[0].Effective != "100" {
+			t.Errorf("meta = %+v, want both warnings", envelope.Meta)
+		}
+	}
+}
+
+func TestNewHTTPTransparent(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHTTP(Config{Transparent: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/?page=0", nil))
+
+	if got := rec.Header().Values("Warning"); !reflect.DeepEqual(got, []string{`199 - "page must be at least 1"`}) {
+		t.Errorf("Warning = %v", got)
+	}
+}